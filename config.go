@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/jessevdk/go-flags"
 	"github.com/yazgazan/jaydiff/diff"
@@ -15,28 +16,88 @@ type files struct {
 }
 
 type config struct {
-	Files  files          `positional-args:"yes" required:"yes"`
-	Ignore ignorePatterns `long:"ignore" short:"i" description:"paths to ignore (glob)"`
+	Files files `positional-args:"yes" required:"yes"`
+	diffOptions
+	Recursive bool `long:"recursive" description:"compare the files of the FILE_1 and FILE_2 directories, paired by relative path"`
+	Git       bool `long:"git" description:"compare the PATH argument between the FILE_1 and FILE_2 git revisions"`
+
+	Version func() `long:"version" short:"v" description:"print release version"`
+
+	GitPath string
+}
+
+// diffOptions are the options shared by the modes comparing two values.
+type diffOptions struct {
+	Ignore      ignorePatterns `long:"ignore" short:"i" description:"paths to ignore (glob)"`
+	IgnoreQuery ignoreQueries  `long:"ignore-query" description:"JSONPath query matching the values to ignore (i.e '$..id')"`
 	output
-	IgnoreExcess  bool `long:"ignore-excess" description:"ignore excess keys and array elements"`
-	IgnoreValues  bool `long:"ignore-values" description:"ignore scalar's values (only type is compared)"`
-	OutputReport  bool `long:"report" short:"r" description:"output report format"`
-	UseSliceMyers bool `long:"slice-myers" description:"use myers algorithm for slices"`
+	IgnoreExcess  bool     `long:"ignore-excess" description:"ignore excess keys and array elements"`
+	IgnoreValues  bool     `long:"ignore-values" description:"ignore scalar's values (only type is compared)"`
+	OutputReport  bool     `long:"report" short:"r" description:"output report format"`
+	OutputFormat  string   `long:"output" short:"o" description:"output format" choice:"jsonpatch" choice:"mergepatch"`
+	UseSliceMyers bool     `long:"slice-myers" description:"use myers algorithm for slices"`
+	Reverse       bool     `long:"reverse" description:"report the changes from FILE_2 to FILE_1"`
+	Format        string   `long:"format" description:"format of FILE_1 and FILE_2 (detected from the file extension by default)" choice:"json" choice:"yaml" choice:"toml" choice:"xml" choice:"csv" choice:"tsv" choice:"msgpack" choice:"cbor"`
+	LHSFormat     string   `long:"lhs-format" description:"format of FILE_1" choice:"json" choice:"yaml" choice:"toml" choice:"xml" choice:"csv" choice:"tsv" choice:"msgpack" choice:"cbor"`
+	RHSFormat     string   `long:"rhs-format" description:"format of FILE_2" choice:"json" choice:"yaml" choice:"toml" choice:"xml" choice:"csv" choice:"tsv" choice:"msgpack" choice:"cbor"`
+	KeyColumns    string   `long:"key-columns" description:"comma-separated keys used to match array elements (i.e table rows) instead of their position"`
+	ArrayKeys     []string `long:"array-key" description:"key used to match the elements of the arrays matching a path instead of their position (i.e .users[]=id)"`
+	Unordered     []string `long:"unordered" description:"ignore the order of the elements of the arrays matching a path (all arrays if no path is given)" optional:"yes" optional-value:"*"`
+
+	UseNumber         bool    `long:"use-number" description:"decode numbers without loss of precision"`
+	FloatTolerance    float64 `long:"float-tolerance" description:"absolute difference under which floating point numbers are considered equal"`
+	FloatRelTolerance float64 `long:"float-rel-tolerance" description:"relative difference under which floating point numbers are considered equal"`
 
 	Stream             bool `long:"stream" description:"treat FILE_1 and FILE_2 as JSON streams"`
 	StreamLines        bool `long:"stream-lines" description:"read JSON stream line by line (expecting 1 JSON value per line)"`
 	StreamIgnoreExcess bool `long:"stream-ignore-excess" description:"ignore excess values in JSON stream"`
 	StreamValidate     bool `long:"stream-validate" description:"compare FILE_2 JSON stream against FILE_1 single value"`
+}
 
-	Version func() `long:"version" short:"v" description:"print release version"`
+type patchFiles struct {
+	File  string `positional-arg-name:"FILE"`
+	Patch string `positional-arg-name:"PATCH"`
+}
+
+type patchConfig struct {
+	Files  patchFiles `positional-args:"yes" required:"yes"`
+	Indent string     `long:"indent" description:"indent string" default:"\t"`
+}
+
+type mergeFiles struct {
+	Base   string `positional-arg-name:"BASE"`
+	Ours   string `positional-arg-name:"OURS"`
+	Theirs string `positional-arg-name:"THEIRS"`
+}
+
+type mergeConfig struct {
+	Files  mergeFiles `positional-args:"yes" required:"yes"`
+	Indent string     `long:"indent" description:"indent string" default:"\t"`
+}
+
+type gitDriverFiles struct {
+	Path    string `positional-arg-name:"PATH"`
+	OldFile string `positional-arg-name:"OLD_FILE"`
+	OldHex  string `positional-arg-name:"OLD_HEX"`
+	OldMode string `positional-arg-name:"OLD_MODE"`
+	NewFile string `positional-arg-name:"NEW_FILE"`
+	NewHex  string `positional-arg-name:"NEW_HEX"`
+	NewMode string `positional-arg-name:"NEW_MODE"`
+}
+
+type gitDriverConfig struct {
+	Files gitDriverFiles `positional-args:"yes" required:"yes"`
+	diffOptions
 }
 
 type output struct {
-	Indent     string `long:"indent" description:"indent string" default:"\t"`
-	ShowTypes  bool   `long:"show-types" short:"t" description:"show types"`
-	Colorized  bool
-	JSON       bool `long:"json" description:"json-style output"`
-	JSONValues bool
+	Indent           string `long:"indent" description:"indent string" default:"\t"`
+	ShowTypes        bool   `long:"show-types" short:"t" description:"show types"`
+	Colorized        bool
+	JSON             bool `long:"json" description:"json-style output"`
+	JSONValues       bool
+	InlineStrings    bool `long:"inline-strings" description:"show the changes between two strings on a single line"`
+	MultilineStrings bool `long:"multiline-strings" description:"show the changes between two multiline strings as unified diff hunks"`
 }
 
 func readConfig() config {
@@ -46,26 +107,126 @@ func readConfig() config {
 		os.Exit(0)
 	}
 
-	_, err := flags.Parse(&c)
-	if err != nil {
-		if flagsErr, ok := err.(*flags.Error); ok && flagsErr.Type == flags.ErrHelp {
-			os.Exit(0)
+	args, err := flags.Parse(&c)
+	exitOnFlagsError(err, os.Args[0])
+
+	c.checkOptions()
+	if c.Git {
+		if len(args) != 1 {
+			fmt.Fprintf(os.Stderr, "Expected a PATH after the revisions when using --git. See %s --help\n", os.Args[0])
+			os.Exit(statusUsage)
 		}
-		fmt.Fprintf(os.Stderr, "Failed to parse arguments. See %s --help\n", os.Args[0])
+		c.GitPath = args[0]
+	}
+	if c.Git && c.Recursive {
+		fmt.Fprintf(os.Stderr, "Incompatible options --git and --recursive\n")
 		os.Exit(statusUsage)
 	}
 
-	if c.JSON && c.ShowTypes {
-		fmt.Fprintf(os.Stderr, "Incompatible options --json and --show-types\n")
+	if c.JSON && c.MultilineStrings {
+		fmt.Fprintf(os.Stderr, "Incompatible options --json and --multiline-strings\n")
+		os.Exit(statusUsage)
+	}
+	if c.Recursive && c.OutputFormat != "" {
+		fmt.Fprintf(os.Stderr, "Incompatible options --recursive and --output\n")
 		os.Exit(statusUsage)
 	}
+	if c.Recursive && (c.Files.LHS == stdinFile || c.Files.RHS == stdinFile) {
+		fmt.Fprintf(os.Stderr, "Directories cannot be read from stdin\n")
+		os.Exit(statusUsage)
+	}
+	if c.Recursive {
+		checkDirectories(os.Args[0], c.Files.LHS, c.Files.RHS)
+	}
 
+	checkStdinFiles(os.Args[0], c.Files.LHS, c.Files.RHS)
 	c.InferFlags()
 
 	return c
 }
 
-func (c *config) InferFlags() {
+func readPatchConfig(args []string) patchConfig {
+	var c patchConfig
+
+	readCommandConfig(cmdPatch, &c, args)
+	checkStdinFiles(os.Args[0]+" "+cmdPatch, c.Files.File, c.Files.Patch)
+
+	return c
+}
+
+func readMergeConfig(args []string) mergeConfig {
+	var c mergeConfig
+
+	readCommandConfig(cmdMerge, &c, args)
+	checkStdinFiles(os.Args[0]+" "+cmdMerge, c.Files.Base, c.Files.Ours, c.Files.Theirs)
+
+	return c
+}
+
+func readGitDriverConfig(args []string) gitDriverConfig {
+	var c gitDriverConfig
+
+	readCommandConfig(cmdGitDriver, &c, args)
+	c.checkOptions()
+	c.InferFlags()
+
+	return c
+}
+
+func readCommandConfig(cmd string, c interface{}, args []string) {
+	name := os.Args[0] + " " + cmd
+	parser := flags.NewNamedParser(name, flags.Default)
+	_, err := parser.AddGroup("Application Options", "", c)
+	if err == nil {
+		_, err = parser.ParseArgs(args)
+	}
+	exitOnFlagsError(err, name)
+}
+
+func exitOnFlagsError(err error, name string) {
+	if err == nil {
+		return
+	}
+	if flagsErr, ok := err.(*flags.Error); ok && flagsErr.Type == flags.ErrHelp {
+		os.Exit(0)
+	}
+	fmt.Fprintf(os.Stderr, "Failed to parse arguments. See %s --help\n", name)
+	os.Exit(statusUsage)
+}
+
+// checkStdinFiles exits if more than one of fnames is to be read from stdin.
+func checkStdinFiles(name string, fnames ...string) {
+	var n int
+
+	for _, fname := range fnames {
+		if fname == stdinFile {
+			n++
+		}
+	}
+	if n > 1 {
+		fmt.Fprintf(os.Stderr, "Only one file can be read from stdin. See %s --help\n", name)
+		os.Exit(statusUsage)
+	}
+}
+
+func (c diffOptions) checkOptions() {
+	if c.JSON && c.ShowTypes {
+		fmt.Fprintf(os.Stderr, "Incompatible options --json and --show-types\n")
+		os.Exit(statusUsage)
+	}
+	for _, arrayKey := range c.ArrayKeys {
+		if !strings.Contains(arrayKey, "=") {
+			fmt.Fprintf(os.Stderr, "Invalid --array-key %q, expected PATH=KEY\n", arrayKey)
+			os.Exit(statusUsage)
+		}
+	}
+	if c.FloatTolerance < 0 || c.FloatRelTolerance < 0 {
+		fmt.Fprintf(os.Stderr, "Invalid negative float tolerance\n")
+		os.Exit(statusUsage)
+	}
+}
+
+func (c *diffOptions) InferFlags() {
 	if c.JSON {
 		c.JSONValues = true
 	}
@@ -76,15 +237,35 @@ func (c *config) InferFlags() {
 		c.Stream = true
 	}
 
+	if c.LHSFormat == "" {
+		c.LHSFormat = c.Format
+	}
+	if c.RHSFormat == "" {
+		c.RHSFormat = c.Format
+	}
+
 	c.output.Colorized = terminal.IsTerminal(int(os.Stdout.Fd()))
 }
 
-func (c config) Opts() []diff.ConfigOpt {
+func (c diffOptions) Opts() []diff.ConfigOpt {
 	opts := []diff.ConfigOpt{}
 
 	if c.UseSliceMyers {
 		opts = append(opts, diff.UseSliceMyers())
 	}
+	if c.KeyColumns != "" {
+		opts = append(opts, diff.KeyedSlices(strings.Split(c.KeyColumns, ",")...))
+	}
+	for _, arrayKey := range c.ArrayKeys {
+		i := strings.LastIndex(arrayKey, "=")
+		opts = append(opts, diff.SliceKey(arrayKey[:i], arrayKey[i+1:]))
+	}
+	if len(c.Unordered) != 0 {
+		opts = append(opts, diff.UnorderedSlices(c.Unordered...))
+	}
+	if c.FloatTolerance != 0 || c.FloatRelTolerance != 0 {
+		opts = append(opts, diff.FloatTolerance(c.FloatTolerance, c.FloatRelTolerance))
+	}
 
 	return opts
 }
@@ -0,0 +1,254 @@
+package diff
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Apply reconstructs the RHS of a diff tree by applying its differences to lhs.
+// lhs is expected to have the same shape as the LHS used to create the diff
+// tree (for streams, lhs should be a slice of the stream's values).
+// Ignored nodes (see Ignore) leave the corresponding lhs values untouched.
+//
+// The lhs value is not modified, maps, slices and structs are copied when
+// they contain differences.
+func Apply(d Differ, lhs interface{}) (interface{}, error) {
+	if d.Diff() == Identical {
+		return lhs, nil
+	}
+
+	switch d := d.(type) {
+	case scalar, types:
+		return RHS(d)
+	case mapDiff:
+		if d.Diff() == TypesDiffer {
+			return d.rhs, nil
+		}
+		return applyMap(d, lhs)
+	case structDiff:
+		if d.Diff() == TypesDiffer {
+			return d.rhs, nil
+		}
+		return applyStruct(d, lhs)
+	case slice:
+		if d.Diff() == TypesDiffer {
+			return d.rhs, nil
+		}
+		return applySlice(d.diffs, lhs)
+	case stream:
+		return applySlice(d.diffs, lhs)
+	}
+
+	return lhs, ErrDifferNotSupported{Diff: d}
+}
+
+func applyMap(m mapDiff, lhs interface{}) (interface{}, error) {
+	lhsVal := reflect.Indirect(reflect.ValueOf(lhs))
+	if lhsVal.Kind() != reflect.Map {
+		return lhs, errLHSMismatch{Value: lhs}
+	}
+
+	res := reflect.MakeMapWithSize(lhsVal.Type(), lhsVal.Len())
+	for _, key := range lhsVal.MapKeys() {
+		res.SetMapIndex(key, lhsVal.MapIndex(key))
+	}
+
+	for key, d := range m.diffs {
+		keyVal := reflect.ValueOf(key)
+
+		switch d := d.(type) {
+		case mapMissing:
+			res.SetMapIndex(keyVal, reflect.Value{})
+		case mapExcess:
+			v, err := valueOfType(lhsVal.Type().Elem(), d.value)
+			if err != nil {
+				return lhs, err
+			}
+			res.SetMapIndex(keyVal, v)
+		default:
+			lhsEl := lhsVal.MapIndex(keyVal)
+			if d.Diff() == Identical || !lhsEl.IsValid() {
+				continue
+			}
+			newEl, err := Apply(d, lhsEl.Interface())
+			if err != nil {
+				return lhs, err
+			}
+			v, err := valueOfType(lhsVal.Type().Elem(), newEl)
+			if err != nil {
+				return lhs, err
+			}
+			res.SetMapIndex(keyVal, v)
+		}
+	}
+
+	return res.Interface(), nil
+}
+
+func applyStruct(s structDiff, lhs interface{}) (interface{}, error) {
+	lhsVal := reflect.Indirect(reflect.ValueOf(lhs))
+	if lhsVal.Kind() != reflect.Struct {
+		return lhs, errLHSMismatch{Value: lhs}
+	}
+
+	res := reflect.New(lhsVal.Type()).Elem()
+	res.Set(lhsVal)
+
+	for name, d := range s.diffs {
+		if d.Diff() == Identical {
+			continue
+		}
+		field := res.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			return lhs, errLHSMismatch{Value: lhs}
+		}
+		newField, err := Apply(d, field.Interface())
+		if err != nil {
+			return lhs, err
+		}
+		v, err := valueOfType(field.Type(), newField)
+		if err != nil {
+			return lhs, err
+		}
+		field.Set(v)
+	}
+
+	return res.Interface(), nil
+}
+
+func applySlice(diffs []Differ, lhs interface{}) (interface{}, error) {
+	lhsVal := reflect.Indirect(reflect.ValueOf(lhs))
+	if lhsVal.Kind() != reflect.Slice && lhsVal.Kind() != reflect.Array {
+		return lhs, errLHSMismatch{Value: lhs}
+	}
+	elType := lhsVal.Type().Elem()
+
+	var moved []sliceMoved
+
+	res := reflect.MakeSlice(reflect.SliceOf(elType), 0, len(diffs))
+	// i is the index of the current element in lhs.
+	i := 0
+	for _, d := range diffs {
+		var (
+			v   reflect.Value
+			err error
+		)
+
+		switch d := d.(type) {
+		case sliceMissing, streamMissing:
+			i++
+			continue
+		case sliceMoved:
+			moved = append(moved, d)
+			i++
+			continue
+		case sliceExcess:
+			v, err = valueOfType(elType, d.value)
+		case streamExcess:
+			v, err = valueOfType(elType, d.value)
+		case ignore:
+			// ignored excess elements have no LHS value, other ignored elements are kept as-is
+			if d.excess {
+				continue
+			}
+			if i >= lhsVal.Len() {
+				return lhs, errLHSMismatch{Value: lhs}
+			}
+			v = lhsVal.Index(i)
+			i++
+		default:
+			if i >= lhsVal.Len() {
+				return lhs, errLHSMismatch{Value: lhs}
+			}
+			v, err = applyElement(d, elType, lhsVal.Index(i))
+			i++
+		}
+		if err != nil {
+			return lhs, err
+		}
+		res = reflect.Append(res, v)
+	}
+
+	res, err := insertMoved(res, moved)
+	if err != nil {
+		return lhs, err
+	}
+	if lhsVal.Kind() == reflect.Array {
+		return sliceToArray(res, lhsVal.Type())
+	}
+
+	return res.Interface(), nil
+}
+
+// insertMoved inserts the moved elements at their RHS index, the other elements being
+// already in place.
+func insertMoved(s reflect.Value, moved []sliceMoved) (reflect.Value, error) {
+	sort.Slice(moved, func(i, j int) bool {
+		return moved[i].to < moved[j].to
+	})
+
+	for _, m := range moved {
+		v, err := valueOfType(s.Type().Elem(), m.value)
+		if err != nil {
+			return s, err
+		}
+		to := m.to
+		if to > s.Len() {
+			to = s.Len()
+		}
+		s = reflect.Append(s, v)
+		reflect.Copy(s.Slice(to+1, s.Len()), s.Slice(to, s.Len()-1))
+		s.Index(to).Set(v)
+	}
+
+	return s, nil
+}
+
+func applyElement(d Differ, elType reflect.Type, lhsEl reflect.Value) (reflect.Value, error) {
+	if d.Diff() == Identical {
+		return lhsEl, nil
+	}
+
+	newEl, err := Apply(d, lhsEl.Interface())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+
+	return valueOfType(elType, newEl)
+}
+
+func sliceToArray(s reflect.Value, typ reflect.Type) (interface{}, error) {
+	if s.Len() != typ.Len() {
+		return s.Interface(), errInvalidType{Value: s.Interface(), For: typ.String()}
+	}
+
+	res := reflect.New(typ).Elem()
+	reflect.Copy(res, s)
+
+	return res.Interface(), nil
+}
+
+// valueOfType returns v as a value that can be assigned to a variable of type typ.
+func valueOfType(typ reflect.Type, v interface{}) (reflect.Value, error) {
+	if v == nil {
+		switch typ.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+			return reflect.Zero(typ), nil
+		}
+		return reflect.Value{}, errInvalidType{Value: v, For: typ.String()}
+	}
+
+	val := reflect.ValueOf(v)
+	switch {
+	case val.Type().AssignableTo(typ):
+		return val, nil
+	case typ.Kind() == reflect.Ptr && val.Type().AssignableTo(typ.Elem()):
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(val)
+		return ptr, nil
+	case val.Kind() == typ.Kind() && val.Type().ConvertibleTo(typ):
+		return val.Convert(typ), nil
+	}
+
+	return reflect.Value{}, errInvalidType{Value: v, For: typ.String()}
+}
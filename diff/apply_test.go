@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	for _, test := range []struct {
+		LHS  interface{}
+		RHS  interface{}
+		Opts []ConfigOpt
+	}{
+		{LHS: 42, RHS: 42},
+		{LHS: 42, RHS: 43},
+		{LHS: 42, RHS: "42"},
+		{LHS: nil, RHS: 42},
+		{LHS: []int{1, 2, 3}, RHS: []int{1, 2, 3}},
+		{LHS: []int{1, 2, 3}, RHS: []int{1, 4}},
+		{LHS: []int{1, 2}, RHS: []int{1, 4, 5}},
+		{LHS: []int{1, 2, 3}, RHS: []float64{1, 2, 3}},
+		{LHS: []int{1, 2, 3}, RHS: []int{0, 1, 2, 5}, Opts: []ConfigOpt{UseSliceMyers()}},
+		{LHS: []int{1, 2, 3, 4, 5}, RHS: []int{2, 3, 6, 5}, Opts: []ConfigOpt{UseSliceMyers()}},
+		{LHS: []int{1, 2, 3, 4}, RHS: []int{4, 2, 5, 3, 1}, Opts: []ConfigOpt{UseSliceMyers()}},
+		{LHS: [3]int{1, 2, 3}, RHS: [3]int{3, 1, 2}, Opts: []ConfigOpt{UseSliceMyers()}},
+		{
+			LHS: []interface{}{
+				map[string]interface{}{"a": 1, "b": 2, "c": 3},
+				map[string]interface{}{"a": 4, "b": 5, "c": 6},
+			},
+			RHS: []interface{}{
+				0,
+				map[string]interface{}{"a": 1, "b": 2, "c": 7},
+				map[string]interface{}{"a": 4, "b": 8, "c": 6},
+			},
+			Opts: []ConfigOpt{UseSliceMyers()},
+		},
+		{LHS: [2]int{1, 2}, RHS: [2]int{1, 3}},
+		{LHS: map[string]int{"a": 1, "b": 2}, RHS: map[string]int{"a": 1, "b": 3, "c": 4}},
+		{LHS: map[string]int{"a": 1, "b": 2}, RHS: map[string]int{"b": 2}},
+		{LHS: map[string]int{"a": 1}, RHS: map[string]float64{"a": 1}},
+		{
+			LHS: map[string]interface{}{
+				"a": []interface{}{1, map[string]interface{}{"b": 2}},
+				"c": nil,
+				"d": "e",
+			},
+			RHS: map[string]interface{}{
+				"a": []interface{}{1, map[string]interface{}{"b": 3, "f": nil}, 4},
+				"c": "g",
+			},
+		},
+		{
+			LHS: structA{Foo: 1, Bar: subStruct{A: 2}},
+			RHS: structA{Foo: 2, Bar: subStruct{A: 3}},
+		},
+		{
+			LHS: map[string]*subStruct{"a": {A: 1}},
+			RHS: map[string]*subStruct{"a": {A: 2}},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		got, err := Apply(d, test.LHS)
+		if err != nil {
+			t.Errorf("Apply(Diff(%+v, %+v), %+v): unexpected error: %s", test.LHS, test.RHS, test.LHS, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.RHS) {
+			t.Errorf("Apply(Diff(%+v, %+v), %+v) = %+v, expected %+v", test.LHS, test.RHS, test.LHS, got, test.RHS)
+		}
+	}
+}
+
+func TestApplyStream(t *testing.T) {
+	d, err := Diff(mockStream(1, 2, 3), mockStream(1, 4))
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+
+	got, err := Apply(d, []interface{}{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Apply: unexpected error: %s", err)
+	}
+	want := []interface{}{1, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestApplyIgnore(t *testing.T) {
+	lhs := map[string]int{"a": 1, "b": 2}
+	rhs := map[string]int{"a": 2, "b": 3}
+	d, err := Diff(lhs, rhs)
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	d, err = Walk(d, func(_, _ Differ, path string) (Differ, error) {
+		if path == ".a" {
+			return Ignore()
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %s", err)
+	}
+
+	got, err := Apply(d, lhs)
+	if err != nil {
+		t.Fatalf("Apply: unexpected error: %s", err)
+	}
+	want := map[string]int{"a": 1, "b": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestApplyIgnoreExcess(t *testing.T) {
+	d, err := Diff([]int{1, 2}, []int{0, 1, 3}, UseSliceMyers())
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	d, err = Walk(d, func(_, d Differ, _ string) (Differ, error) {
+		if IsExcess(d) {
+			return Ignore()
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %s", err)
+	}
+
+	got, err := Apply(d, []int{1, 2})
+	if err != nil {
+		t.Fatalf("Apply: unexpected error: %s", err)
+	}
+	want := []int{1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestApplyErrors(t *testing.T) {
+	for _, test := range []struct {
+		Diff Differ
+		LHS  interface{}
+	}{
+		{unsupportedDiffer{}, 42},
+		{mapDiff{lhs: map[string]int{}, rhs: map[string]int{}, diffs: map[interface{}]Differ{"a": mapMissing{1}}}, 42},
+		{slice{lhs: []int{}, rhs: []int{}, diffs: []Differ{sliceMissing{1}}}, 42},
+		{slice{lhs: []int{}, rhs: []int{}, diffs: []Differ{scalar{lhs: 1, rhs: 2}}}, []int{}},
+		{structDiff{lhs: structA{}, rhs: structA{}, diffs: map[string]Differ{"Foo": scalar{lhs: 1, rhs: 2}}}, 42},
+		{
+			mapDiff{lhs: map[string]int{}, rhs: map[string]int{}, diffs: map[interface{}]Differ{"a": mapExcess{"b"}}},
+			map[string]int{},
+		},
+	} {
+		_, err := Apply(test.Diff, test.LHS)
+		if err == nil {
+			t.Errorf("Apply(%+v, %+v): expected error, got nil", test.Diff, test.LHS)
+		}
+	}
+}
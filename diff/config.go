@@ -1,7 +1,36 @@
 package diff
 
+import (
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
 type config struct {
-	sliceFn diffFn
+	sliceFn    diffFn
+	sliceKeys  []sliceKey
+	unordered  []glob.Glob
+	tolerances []floatTolerance
+	err        error
+
+	// path is the path of the values being compared (as passed to WalkFn)
+	path string
+}
+
+// sliceKey holds the keys used to match the elements of the slices matching glob
+// (all slices if glob is nil).
+type sliceKey struct {
+	glob glob.Glob
+	keys []string
+}
+
+// floatTolerance holds the tolerances used to compare the floats matching glob (all floats if
+// glob is nil).
+type floatTolerance struct {
+	glob glob.Glob
+	abs  float64
+	rel  float64
 }
 
 // ConfigOpt is used to pass configuration options to the diff algorithm
@@ -13,10 +42,167 @@ func defaultConfig() config {
 	}
 }
 
-// UseSliceMyers configures the Diff function to use Myers' algorithm for slices
+// at returns the configuration used to compare the values found at elem, relative to c.path.
+func (c config) at(elem string) config {
+	c.path += elem
+
+	return c
+}
+
+// keysAt returns the keys used to match the elements of the slice at c.path, if any.
+// Keys configured using SliceKey take precedence over the ones configured using KeyedSlices.
+func (c config) keysAt() []string {
+	var keys []string
+
+	path := jpath.StripIndices(c.path)
+	for _, k := range c.sliceKeys {
+		if k.glob == nil {
+			keys = k.keys
+			continue
+		}
+		if k.glob.Match(path) {
+			return k.keys
+		}
+	}
+
+	return keys
+}
+
+// unorderedAt reports whether the slice at c.path is to be compared as a multiset.
+func (c config) unorderedAt() bool {
+	path := jpath.StripIndices(c.path)
+	for _, g := range c.unordered {
+		if g == nil || g.Match(path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toleranceAt returns the tolerances used to compare the floats at c.path.
+// Tolerances configured for specific paths take precedence over the ones applying to all floats.
+func (c config) toleranceAt() floatTolerance {
+	var tolerance floatTolerance
+
+	path := jpath.StripIndices(c.path)
+	for _, t := range c.tolerances {
+		if t.glob == nil {
+			tolerance = t
+			continue
+		}
+		if t.glob.Match(path) {
+			return t
+		}
+	}
+
+	return tolerance
+}
+
+// UseSliceMyers configures the Diff function to use Myers' algorithm for slices.
+// Elements removed at one index and inserted identically at another are reported as
+// moved (see IsMoved), and removed and inserted containers that are mostly identical are
+// compared with each other.
 func UseSliceMyers() ConfigOpt {
 	return func(c config) config {
 		c.sliceFn = newMyersSlice
 		return c
 	}
 }
+
+// KeyedSlices configures the Diff function to match the elements of slices of maps using
+// the values of the given keys instead of their position. Elements are then identified by their
+// keys in paths (i.e `[id=42]`).
+// Slices containing elements that are not maps or that are missing one of the keys are compared
+// using the configured slice algorithm.
+//
+// The order of the elements is not compared. When applying the resulting diff (see Apply and
+// JSONPatch), the matched elements keep the order of the LHS and new elements are appended.
+func KeyedSlices(keys ...string) ConfigOpt {
+	return func(c config) config {
+		c.sliceKeys = append(c.sliceKeys, sliceKey{keys: keys})
+		return c
+	}
+}
+
+// SliceKey is similar to KeyedSlices, but only applies to the slices matching pathGlob.
+// pathGlob is matched against the path of the slices with their indices stripped
+// (i.e `.users[]` or `.groups[].members[]`). The trailing `[]` is optional.
+//
+// Diff returns an error if pathGlob is not a valid glob.
+func SliceKey(pathGlob, keyField string) ConfigOpt {
+	return func(c config) config {
+		g, err := compilePathGlob(pathGlob)
+		if err != nil {
+			c.err = err
+			return c
+		}
+		c.sliceKeys = append(c.sliceKeys, sliceKey{
+			glob: g,
+			keys: []string{keyField},
+		})
+		return c
+	}
+}
+
+// compilePathGlob compiles a glob matching the paths of slices stripped of their indices.
+func compilePathGlob(pathGlob string) (glob.Glob, error) {
+	return compileGlob(strings.TrimSuffix(pathGlob, "[]"))
+}
+
+// compileGlob compiles a glob matching paths stripped of their indices. Empty brackets are
+// matched literally.
+func compileGlob(pathGlob string) (glob.Glob, error) {
+	return glob.Compile(strings.Replace(pathGlob, "[]", `\[\]`, -1))
+}
+
+// UnorderedSlices configures the Diff function to compare the slices matching one of pathGlobs
+// (or all slices if no glob is given) as multisets: the order of the elements is ignored and
+// only the elements that are not found the same number of times on both sides are reported
+// as missing or excess. pathGlobs are matched as described in SliceKey.
+//
+// When applying the resulting diff (see Apply and JSONPatch), the remaining elements keep the
+// order of the LHS and new elements are appended.
+func UnorderedSlices(pathGlobs ...string) ConfigOpt {
+	return func(c config) config {
+		if len(pathGlobs) == 0 {
+			c.unordered = append(c.unordered, nil)
+			return c
+		}
+		for _, pathGlob := range pathGlobs {
+			g, err := compilePathGlob(pathGlob)
+			if err != nil {
+				c.err = err
+				return c
+			}
+			c.unordered = append(c.unordered, g)
+		}
+		return c
+	}
+}
+
+// FloatTolerance configures the Diff function to consider two floats as identical when the
+// absolute difference between them is at most abs, or at most rel times the largest of their
+// absolute values.
+// The tolerances only apply to the floats matching one of pathGlobs (or all floats if no glob is
+// given). pathGlobs are matched against the path of the values with their indices stripped (i.e
+// `.metrics.*` or `.runs[].loss`).
+//
+// Diff returns an error if one of pathGlobs is not a valid glob.
+func FloatTolerance(abs, rel float64, pathGlobs ...string) ConfigOpt {
+	return func(c config) config {
+		if len(pathGlobs) == 0 {
+			c.tolerances = append(c.tolerances, floatTolerance{abs: abs, rel: rel})
+			return c
+		}
+		for _, pathGlob := range pathGlobs {
+			g, err := compileGlob(pathGlob)
+			if err != nil {
+				c.err = err
+				return c
+			}
+			c.tolerances = append(c.tolerances, floatTolerance{glob: g, abs: abs, rel: rel})
+		}
+		return c
+	}
+}
@@ -38,6 +38,9 @@ func Diff(lhs, rhs interface{}, opts ...ConfigOpt) (Differ, error) {
 	for _, opt := range opts {
 		c = opt(c)
 	}
+	if c.err != nil {
+		return nil, c.err
+	}
 
 	return diff(c, lhs, rhs, &visited{})
 }
@@ -65,7 +68,10 @@ func diffValues(c config, lhs, rhs reflect.Value, visited *visited) (Differ, err
 	}
 
 	if valueIsScalar(lhs) && valueIsScalar(rhs) {
-		return scalar{lhs.Interface(), rhs.Interface()}, nil
+		return newScalar(c, lhs, rhs), nil
+	}
+	if valueIsBytes(lhs) && valueIsBytes(rhs) {
+		return scalar{lhs: lhs.Interface(), rhs: rhs.Interface()}, nil
 	}
 	if lhs.Kind() != rhs.Kind() {
 		return types{lhs.Interface(), rhs.Interface()}, nil
@@ -73,7 +79,7 @@ func diffValues(c config, lhs, rhs reflect.Value, visited *visited) (Differ, err
 
 	switch lhs.Kind() {
 	case reflect.Slice, reflect.Array:
-		return c.sliceFn(c, lhs.Interface(), rhs.Interface(), visited)
+		return diffSlices(c, lhs.Interface(), rhs.Interface(), visited)
 	case reflect.Map:
 		return newMap(c, lhs.Interface(), rhs.Interface(), visited)
 	case reflect.Struct:
@@ -83,6 +89,21 @@ func diffValues(c config, lhs, rhs reflect.Value, visited *visited) (Differ, err
 	return types{lhs.Interface(), rhs.Interface()}, &ErrUnsupported{lhs.Type(), rhs.Type()}
 }
 
+func diffSlices(c config, lhs, rhs interface{}, visited *visited) (Differ, error) {
+	if keys := c.keysAt(); len(keys) != 0 {
+		if d, ok, err := newKeyedSlice(c, keys, lhs, rhs, visited); ok {
+			return d, err
+		}
+	}
+	if c.unorderedAt() {
+		if d, ok, err := newUnorderedSlice(c, lhs, rhs, visited); ok {
+			return d, err
+		}
+	}
+
+	return c.sliceFn(c, lhs, rhs, visited)
+}
+
 func indirectValueOf(i interface{}) (reflect.Value, interface{}) {
 	if _, ok := i.(Stream); ok {
 		return reflect.ValueOf(i), i
@@ -115,15 +136,32 @@ func valueIsScalar(v reflect.Value) bool {
 	}
 }
 
+// valueIsBytes reports whether v is a byte slice. Byte slices are compared as a
+// single value rather than element by element.
+func valueIsBytes(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8
+}
+
+// bytesOf returns the content of i if it is a byte slice (including named types such as
+// json.RawMessage).
+func bytesOf(i interface{}) ([]byte, bool) {
+	v := reflect.ValueOf(i)
+	if !valueIsBytes(v) {
+		return nil, false
+	}
+
+	return v.Bytes(), true
+}
+
 func nilCheck(lhsVal, rhsVal reflect.Value, lhs, rhs interface{}) (Differ, bool) {
 	lhsNil := lhs == nil || (lhsVal.Kind() == reflect.Ptr && lhsVal.IsNil())
 	rhsNil := rhs == nil || (rhsVal.Kind() == reflect.Ptr && rhsVal.IsNil())
 
 	if lhsNil && rhsNil {
-		return scalar{lhsVal, rhsVal}, true
+		return scalar{lhs: lhs, rhs: rhs}, true
 	}
 	if lhsNil || rhsNil {
-		return types{lhsVal, rhsVal}, true
+		return types{lhs, rhs}, true
 	}
 
 	return nil, false
@@ -162,6 +200,14 @@ func IsMissing(d Differ) bool {
 	}
 }
 
+// IsMoved returns true if d represent an array element found at different positions in the LHS and RHS
+// (see UseSliceMyers)
+func IsMoved(d Differ) bool {
+	_, ok := d.(sliceMoved)
+
+	return ok
+}
+
 // IsScalar returns true of d is a diff between two values that can be compared (int, float64, string, ...)
 func IsScalar(d Differ) bool {
 	_, ok := d.(scalar)
@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -61,6 +62,17 @@ func TestDiff(t *testing.T) {
 		{LHS: []int{1, 2, 3}, RHS: []int{4, 5}, Want: ContentDiffer},
 		{LHS: []int{1, 2, 3}, RHS: []float64{4, 5}, Want: TypesDiffer},
 		{LHS: []int{1, 2, 3}, RHS: []float64{4, 5}, Want: TypesDiffer},
+		{LHS: []byte("abc"), RHS: []byte("abc"), Want: Identical},
+		{LHS: []byte("abc"), RHS: []byte("abd"), Want: ContentDiffer},
+		{LHS: []byte("abc"), RHS: "abc", Want: TypesDiffer},
+		{LHS: json.RawMessage("1"), RHS: json.RawMessage("1"), Want: Identical},
+		{LHS: json.RawMessage("1"), RHS: json.RawMessage("2"), Want: ContentDiffer},
+		{LHS: json.RawMessage("1"), RHS: []byte("1"), Want: ContentDiffer},
+		{
+			LHS:  struct{ V json.RawMessage }{json.RawMessage("1")},
+			RHS:  struct{ V json.RawMessage }{json.RawMessage("2")},
+			Want: ContentDiffer,
+		},
 		{LHS: []func(){func() {}}, RHS: []func(){func() {}}, Want: ContentDiffer, Error: true},
 		{LHS: map[int]int{2: 4, 6: 12}, RHS: map[int]int{2: 4, 6: 12}, Want: Identical},
 		{LHS: map[int]int{2: 4, 6: 12, 8: 16}, RHS: map[int]int{2: 4, 6: 12}, Want: ContentDiffer},
@@ -381,8 +393,59 @@ func TestScalar(t *testing.T) {
 			},
 			Type: TypesDiffer,
 		},
+		{
+			LHS: []byte{0xca, 0xfe},
+			RHS: []byte{0xca, 0xfe},
+			Want: [][]string{
+				{"[]uint8", "0xcafe"},
+			},
+			Type: Identical,
+		},
+		{
+			LHS: []byte{0xca, 0xfe},
+			RHS: []byte{0xbe, 0xef},
+			Want: [][]string{
+				{"[]uint8", "0xcafe"},
+				{"[]uint8", "0xbeef"},
+			},
+			Type: ContentDiffer,
+		},
+		{
+			LHS: json.RawMessage{0xca, 0xfe},
+			RHS: json.RawMessage{0xbe, 0xef},
+			Want: [][]string{
+				{"0xcafe"},
+				{"0xbeef"},
+			},
+			Type: ContentDiffer,
+		},
+		{
+			LHS: json.Number("1.0"),
+			RHS: json.Number("1"),
+			Want: [][]string{
+				{"json.Number", "1.0"},
+			},
+			Type: Identical,
+		},
+		{
+			LHS: json.Number("1e3"),
+			RHS: json.Number("1000"),
+			Want: [][]string{
+				{"json.Number", "1e3"},
+			},
+			Type: Identical,
+		},
+		{
+			LHS: json.Number("1152921504606846977"),
+			RHS: json.Number("1152921504606846976"),
+			Want: [][]string{
+				{"json.Number", "1152921504606846977"},
+				{"json.Number", "1152921504606846976"},
+			},
+			Type: ContentDiffer,
+		},
 	} {
-		typ := scalar{test.LHS, test.RHS}
+		typ := scalar{lhs: test.LHS, rhs: test.RHS}
 
 		if typ.Diff() != test.Type {
 			t.Errorf("Types.Diff() = %q, expected %q", typ.Diff(), test.Type)
@@ -394,6 +457,66 @@ func TestScalar(t *testing.T) {
 	}
 }
 
+func TestFloatTolerance(t *testing.T) {
+	for _, test := range []struct {
+		LHS  interface{}
+		RHS  interface{}
+		Opts []ConfigOpt
+		Type Type
+	}{
+		{LHS: 1.0, RHS: 1.0000001, Type: ContentDiffer},
+		{LHS: 1.0, RHS: 1.0000001, Opts: []ConfigOpt{FloatTolerance(1e-6, 0)}, Type: Identical},
+		{LHS: 1.0, RHS: 1.1, Opts: []ConfigOpt{FloatTolerance(1e-6, 0)}, Type: ContentDiffer},
+		{LHS: 1000.0, RHS: 1000.5, Opts: []ConfigOpt{FloatTolerance(0, 1e-3)}, Type: Identical},
+		{LHS: 1.0, RHS: 1.5, Opts: []ConfigOpt{FloatTolerance(0, 1e-3)}, Type: ContentDiffer},
+		{LHS: float32(1), RHS: float32(1.001), Opts: []ConfigOpt{FloatTolerance(0.01, 0)}, Type: Identical},
+		{LHS: 1, RHS: 2, Opts: []ConfigOpt{FloatTolerance(10, 0)}, Type: ContentDiffer},
+		{LHS: json.Number("0.25"), RHS: json.Number("0.2500001"), Opts: []ConfigOpt{FloatTolerance(1e-6, 0)}, Type: Identical},
+		{LHS: json.Number("0.25"), RHS: json.Number("0.26"), Opts: []ConfigOpt{FloatTolerance(1e-6, 0)}, Type: ContentDiffer},
+		{
+			LHS:  map[string]interface{}{"loss": 0.25, "count": 3.0},
+			RHS:  map[string]interface{}{"loss": 0.2500001, "count": 3.0},
+			Opts: []ConfigOpt{FloatTolerance(1e-6, 0)},
+			Type: Identical,
+		},
+		{
+			LHS:  map[string]interface{}{"metrics": map[string]interface{}{"loss": 0.25}, "count": 3.0},
+			RHS:  map[string]interface{}{"metrics": map[string]interface{}{"loss": 0.2500001}, "count": 3.0000001},
+			Opts: []ConfigOpt{FloatTolerance(1e-6, 0, ".metrics.*")},
+			Type: ContentDiffer,
+		},
+		{
+			LHS:  map[string]interface{}{"runs": []interface{}{0.25, 0.5}},
+			RHS:  map[string]interface{}{"runs": []interface{}{0.2500001, 0.5000001}},
+			Opts: []ConfigOpt{FloatTolerance(1e-6, 0, ".runs[]")},
+			Type: Identical,
+		},
+		{
+			LHS:  map[string]interface{}{"a": 1.0, "b": 1.0},
+			RHS:  map[string]interface{}{"a": 1.05, "b": 1.05},
+			Opts: []ConfigOpt{FloatTolerance(0.1, 0), FloatTolerance(0.01, 0, ".b")},
+			Type: ContentDiffer,
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		if d.Diff() != test.Type {
+			t.Errorf("Diff(%+v, %+v).Diff() = %q, expected %q", test.LHS, test.RHS, d.Diff(), test.Type)
+		}
+		if got := Invert(d).Diff(); got != test.Type {
+			t.Errorf("Invert(Diff(%+v, %+v)).Diff() = %q, expected %q", test.LHS, test.RHS, got, test.Type)
+		}
+	}
+
+	_, err := Diff(1.0, 1.0, FloatTolerance(0.1, 0, "[a-"))
+	if err == nil {
+		t.Errorf("Diff(FloatTolerance(0.1, 0, %q)): expected an error", "[a-")
+	}
+}
+
 type emptyStruct struct{}
 type subStruct struct {
 	A int
@@ -917,6 +1040,20 @@ func TestIsTypes(t *testing.T) {
 	}
 }
 
+func TestIsMoved(t *testing.T) {
+	d, err := Diff([]int{1, 2, 3}, []int{2, 3, 1}, UseSliceMyers())
+	if err != nil {
+		t.Errorf("Diff([]int, []int): unexpected error: %s", err)
+		return
+	}
+	if !IsMoved(d.(slice).diffs[0]) {
+		t.Errorf("IsMoved(Diff([]int{1, 2, 3}, []int{2, 3, 1}).diffs[0]) = false, expected true")
+	}
+	if IsMoved(d) {
+		t.Errorf("IsMoved(Diff([]int{1, 2, 3}, []int{2, 3, 1})) = true, expected false")
+	}
+}
+
 func TestIsIgnore(t *testing.T) {
 	d, err := Ignore()
 	if err != nil {
@@ -51,3 +51,21 @@ type errInvalidStream struct {
 func (e errInvalidStream) Error() string {
 	return fmt.Sprintf("%T does not implement the Stream interface", e.Value)
 }
+
+// ErrDifferNotSupported is returned when a Differ cannot be handled
+// (i.e a Differ implemented outside of this package)
+type ErrDifferNotSupported struct {
+	Diff Differ
+}
+
+func (e ErrDifferNotSupported) Error() string {
+	return fmt.Sprintf("%T is not supported", e.Diff)
+}
+
+type errLHSMismatch struct {
+	Value interface{}
+}
+
+func (e errLHSMismatch) Error() string {
+	return fmt.Sprintf("%T does not match the LHS of the diff", e.Value)
+}
@@ -190,3 +190,27 @@ func ExampleWalk() {
 	//    c: string abc
 	//  ]
 }
+
+func ExampleWalkV2() {
+	lhs := map[string]interface{}{
+		"a.b": []interface{}{1, 2},
+		"c":   map[string]interface{}{"d/e": "f"},
+	}
+	rhs := map[string]interface{}{
+		"a.b": []interface{}{1, 3},
+		"c":   map[string]interface{}{"d/e": "g"},
+	}
+
+	d, _ := diff.Diff(lhs, rhs)
+	_, _ = diff.WalkV2(d, func(parent diff.Differ, d diff.Differ, path diff.Path) (diff.Differ, error) {
+		if diff.IsScalar(d) && d.Diff() != diff.Identical {
+			fmt.Println(path.String(), path.JSONPointer(), path.JSONPath())
+		}
+
+		return nil, nil
+	})
+
+	// Output:
+	// ."a.b"[1] /a.b/1 $['a.b'][1]
+	// .c.d/e /c/d~1e $.c['d/e']
+}
@@ -1,6 +1,12 @@
 package diff
 
-type ignore struct{}
+type ignore struct {
+	// excess and missing record whether the ignored node is an element missing from the LHS
+	// or from the RHS (see IsExcess and IsMissing), keeping track of the position of the
+	// following elements in slices.
+	excess  bool
+	missing bool
+}
 
 // Ignore can be used in a WalkFn to ignore a non-matching diff.
 // (See Walk example)
@@ -8,6 +14,37 @@ func Ignore() (Differ, error) {
 	return ignore{}, nil
 }
 
+// ignoring returns the node replacing old, recording which side old was missing from
+// if d is an ignore node.
+func ignoring(old, d Differ) Differ {
+	if _, ok := d.(ignore); !ok {
+		return d
+	}
+	if i, ok := old.(ignore); ok {
+		return i
+	}
+
+	return ignore{excess: IsExcess(old), missing: IsMissing(old)}
+}
+
+// isExcessElement returns true if d is an element missing from the LHS, ignored or not.
+func isExcessElement(d Differ) bool {
+	if i, ok := d.(ignore); ok {
+		return i.excess
+	}
+
+	return IsExcess(d)
+}
+
+// isMissingElement returns true if d is an element missing from the RHS, ignored or not.
+func isMissingElement(d Differ) bool {
+	if i, ok := d.(ignore); ok {
+		return i.missing
+	}
+
+	return IsMissing(d)
+}
+
 func (t ignore) Diff() Type {
 	return Identical
 }
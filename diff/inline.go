@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	myersdiff "github.com/mb0/diff"
+)
+
+// inlineGranularity is the minimum length of the unchanged segments of an inline string diff.
+// Shorter unchanged segments are merged with the surrounding changes to keep the output readable.
+const inlineGranularity = 4
+
+// inlineString renders the differences between lhs and rhs on a single line, highlighting the
+// removed (red) and inserted (green) segments. Without colors, the segments are delimited using
+// `[-removed-]` and `{+inserted+}`. Strings containing newlines (or other characters that need
+// escaping) are quoted to stay on a single line.
+func (o Output) inlineString(lhs, rhs string) string {
+	quoted := o.JSONValues || needsQuoting(lhs) || needsQuoting(rhs)
+	lhsRunes := []rune(lhs)
+	rhsRunes := []rune(rhs)
+	changes := myersdiff.Granular(inlineGranularity, myersdiff.Runes(lhsRunes, rhsRunes))
+
+	var b strings.Builder
+	lhsIdx := 0
+	for _, c := range changes {
+		del := lhsRunes[c.A : c.A+c.Del]
+		ins := rhsRunes[c.B : c.B+c.Ins]
+		// merging changes can leave a common prefix or suffix on both sides
+		prefix := commonPrefix(del, ins)
+		suffix := commonPrefix(reverseRunes(del[prefix:]), reverseRunes(ins[prefix:]))
+
+		b.WriteString(o.inlineSegment(string(lhsRunes[lhsIdx:c.A+prefix]), quoted, nil, "", ""))
+		b.WriteString(o.inlineSegment(string(del[prefix:len(del)-suffix]), quoted, color.RedString, "[-", "-]"))
+		b.WriteString(o.inlineSegment(string(ins[prefix:len(ins)-suffix]), quoted, color.GreenString, "{+", "+}"))
+		b.WriteString(o.inlineSegment(string(del[len(del)-suffix:]), quoted, nil, "", ""))
+		lhsIdx = c.A + c.Del
+	}
+	b.WriteString(o.inlineSegment(string(lhsRunes[lhsIdx:]), quoted, nil, "", ""))
+
+	s := b.String()
+	if quoted {
+		s = `"` + s + `"`
+	}
+	if o.ShowTypes {
+		s = "string " + s
+	}
+
+	return s
+}
+
+func (o Output) inlineSegment(s string, quoted bool, fn colorFn, open, close string) string {
+	if s == "" {
+		return ""
+	}
+	switch {
+	case o.JSONValues:
+		s = strings.TrimSuffix(strings.TrimPrefix(jsonString(s), `"`), `"`)
+	case quoted:
+		s = strings.TrimSuffix(strings.TrimPrefix(strconv.Quote(s), `"`), `"`)
+	}
+	if fn == nil {
+		return s
+	}
+	if o.Colorized {
+		return fn("%s", s)
+	}
+
+	return open + s + close
+}
+
+func commonPrefix(a, b []rune) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+func reverseRunes(r []rune) []rune {
+	res := make([]rune, len(r))
+	for i, c := range r {
+		res[len(r)-1-i] = c
+	}
+
+	return res
+}
+
+// needsQuoting returns true if s contains characters that are escaped when quoted (such as
+// newlines).
+func needsQuoting(s string) bool {
+	return strconv.Quote(s) != `"`+s+`"`
+}
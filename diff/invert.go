@@ -0,0 +1,213 @@
+package diff
+
+import "sort"
+
+// Invert returns a diff tree describing the changes from the RHS to the LHS of d.
+// LHS and RHS values are swapped and missing values become excess values (and vice-versa).
+// Differs that are not part of this package are returned as-is.
+func Invert(d Differ) Differ {
+	switch d := d.(type) {
+	case scalar:
+		return scalar{lhs: d.rhs, rhs: d.lhs, tolerance: d.tolerance}
+	case types:
+		return types{d.rhs, d.lhs}
+	case mapMissing:
+		return mapExcess(d)
+	case mapExcess:
+		return mapMissing(d)
+	case sliceMissing:
+		return sliceExcess(d)
+	case sliceExcess:
+		return sliceMissing(d)
+	case sliceMoved:
+		return sliceMoved{value: d.value, from: d.to, to: d.from}
+	case streamMissing:
+		return streamExcess(d)
+	case streamExcess:
+		return streamMissing(d)
+	case ignore:
+		return ignore{excess: d.missing, missing: d.excess}
+	case mapDiff:
+		return invertMap(d)
+	case structDiff:
+		return invertStruct(d)
+	case slice:
+		return invertSlice(d)
+	case stream:
+		return invertStream(d)
+	}
+
+	return d
+}
+
+func invertMap(m mapDiff) mapDiff {
+	diffs := make(map[interface{}]Differ, len(m.diffs))
+	for key, d := range m.diffs {
+		diffs[key] = Invert(d)
+	}
+
+	return mapDiff{
+		lhs:   m.rhs,
+		rhs:   m.lhs,
+		diffs: diffs,
+	}
+}
+
+func invertStruct(s structDiff) structDiff {
+	diffs := make(map[string]Differ, len(s.diffs))
+	for key, d := range s.diffs {
+		diffs[key] = Invert(d)
+	}
+
+	return structDiff{
+		lhs:   s.rhs,
+		rhs:   s.lhs,
+		diffs: diffs,
+	}
+}
+
+func invertStream(s stream) stream {
+	diffs := make([]Differ, len(s.diffs))
+	for i, d := range s.diffs {
+		diffs[i] = Invert(d)
+	}
+
+	return stream{
+		lhs:     s.rhs,
+		rhs:     s.lhs,
+		diffs:   diffs,
+		indices: s.indices,
+	}
+}
+
+// invertSlice swaps the LHS and RHS of a slice diff. Within a run of missing and
+// excess elements, the (new) missing elements are placed first, and indices are
+// recomputed relative to the new LHS. Moved elements are relocated to their new LHS
+// index.
+func invertSlice(s slice) slice {
+	if s.pairs != nil {
+		return invertPairedSlice(s)
+	}
+
+	var (
+		diffs   []Differ
+		indices []int
+		missing []Differ
+		excess  []Differ
+		moved   []sliceMoved
+		lhsIdx  int
+	)
+
+	flushRun := func() {
+		runStart := lhsIdx
+		for _, d := range missing {
+			diffs = append(diffs, d)
+			indices = append(indices, lhsIdx)
+			lhsIdx++
+		}
+		for i, d := range excess {
+			diffs = append(diffs, d)
+			indices = append(indices, runStart+i)
+		}
+		missing, excess = nil, nil
+	}
+
+	for _, d := range s.diffs {
+		switch {
+		case isExcessElement(d):
+			missing = append(missing, Invert(d))
+		case isMissingElement(d):
+			excess = append(excess, Invert(d))
+		case IsMoved(d):
+			moved = append(moved, Invert(d).(sliceMoved))
+		default:
+			flushRun()
+			diffs = append(diffs, Invert(d))
+			indices = append(indices, lhsIdx)
+			lhsIdx++
+		}
+	}
+	flushRun()
+	if len(moved) != 0 {
+		diffs = insertMovedDiffs(diffs, moved)
+		indices = sliceIndices(diffs)
+	}
+
+	return slice{
+		lhs:     s.rhs,
+		rhs:     s.lhs,
+		diffs:   diffs,
+		indices: indices,
+	}
+}
+
+// insertMovedDiffs inserts the moved elements in diffs so that each one is preceded by
+// as many LHS elements as its LHS index.
+func insertMovedDiffs(diffs []Differ, moved []sliceMoved) []Differ {
+	sort.Slice(moved, func(i, j int) bool {
+		return moved[i].from < moved[j].from
+	})
+
+	for _, m := range moved {
+		pos := len(diffs)
+		n := 0
+		for i, d := range diffs {
+			if n == m.from {
+				pos = i
+				break
+			}
+			if !isExcessElement(d) {
+				n++
+			}
+		}
+		diffs = append(diffs, nil)
+		copy(diffs[pos+1:], diffs[pos:])
+		diffs[pos] = m
+	}
+
+	return diffs
+}
+
+// invertPairedSlice swaps the LHS and RHS of a slice diff created using KeyedSlices or
+// UnorderedSlices. The elements are re-ordered to follow the new LHS (the old RHS), the (new)
+// excess elements being placed last.
+func invertPairedSlice(s slice) slice {
+	order := make([]int, 0, len(s.diffs))
+	for i, j := range s.pairs {
+		if j != -1 {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return s.pairs[order[a]] < s.pairs[order[b]]
+	})
+	for i, j := range s.pairs {
+		if j == -1 {
+			order = append(order, i)
+		}
+	}
+
+	res := slice{
+		lhs: s.rhs,
+		rhs: s.lhs,
+	}
+	for _, i := range order {
+		res.diffs = append(res.diffs, Invert(s.diffs[i]))
+		if s.labels != nil {
+			res.labels = append(res.labels, s.labels[i])
+		}
+		switch {
+		case isExcessElement(s.diffs[i]):
+			res.indices = append(res.indices, s.pairs[i])
+			res.pairs = append(res.pairs, -1)
+		case isMissingElement(s.diffs[i]):
+			res.indices = append(res.indices, s.indices[i])
+			res.pairs = append(res.pairs, s.indices[i])
+		default:
+			res.indices = append(res.indices, s.pairs[i])
+			res.pairs = append(res.pairs, s.indices[i])
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInvert(t *testing.T) {
+	for _, test := range []struct {
+		LHS interface{}
+		RHS interface{}
+	}{
+		{LHS: 42, RHS: 42},
+		{LHS: 42, RHS: 43},
+		{LHS: 42, RHS: "42"},
+		{LHS: nil, RHS: 42},
+		{LHS: []int{1, 2, 3}, RHS: []int{1, 4}},
+		{LHS: []int{1, 2, 3}, RHS: []float64{1, 2, 3}},
+		{LHS: map[string]int{"a": 1, "b": 2}, RHS: map[string]int{"a": 3, "c": 4}},
+		{
+			LHS: map[string]interface{}{
+				"a": []interface{}{1, map[string]interface{}{"b": 2}},
+				"c": nil,
+				"d": "e",
+			},
+			RHS: map[string]interface{}{
+				"a": []interface{}{1, map[string]interface{}{"b": 3, "f": nil}, 4},
+				"c": "g",
+			},
+		},
+		{
+			LHS: structA{Foo: 1, Bar: subStruct{A: 2}},
+			RHS: structA{Foo: 2, Bar: subStruct{A: 3}},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		want, err := Diff(test.RHS, test.LHS)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.RHS, test.LHS, err)
+			continue
+		}
+
+		got := Invert(d)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Invert(Diff(%+v, %+v)) = %#v, expected %#v", test.LHS, test.RHS, got, want)
+		}
+	}
+}
+
+func TestInvertMyers(t *testing.T) {
+	for _, test := range []struct {
+		LHS []int
+		RHS []int
+	}{
+		{LHS: []int{1, 2, 3}, RHS: []int{0, 1, 2, 5}},
+		{LHS: []int{1, 2, 3, 4, 5}, RHS: []int{2, 3, 6, 5}},
+		{LHS: []int{1, 2}, RHS: []int{1, 5, 6, 2}},
+		{LHS: []int{1, 2, 3, 4}, RHS: []int{4, 2, 5, 3, 1}},
+		{LHS: []int{1, 2, 3, 4, 5, 6}, RHS: []int{6, 5, 4, 3, 2, 1}},
+	} {
+		d, err := Diff(test.LHS, test.RHS, UseSliceMyers())
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		inverted := Invert(d)
+		got, err := Apply(inverted, test.RHS)
+		if err != nil {
+			t.Errorf("Apply(Invert(Diff(%+v, %+v))): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.LHS) {
+			t.Errorf("Apply(Invert(Diff(%+v, %+v))) = %+v, expected %+v", test.LHS, test.RHS, got, test.LHS)
+		}
+
+		var paths []string
+		_, err = Walk(inverted, func(_, _ Differ, path string) (Differ, error) {
+			paths = append(paths, path)
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("Walk(Invert(Diff(%+v, %+v))): unexpected error: %s", test.LHS, test.RHS, err)
+		}
+		if len(paths) != len(inverted.(slice).diffs)+1 {
+			t.Errorf("Walk(Invert(Diff(%+v, %+v))): got paths %v", test.LHS, test.RHS, paths)
+		}
+	}
+}
+
+func TestInvertStream(t *testing.T) {
+	d, err := Diff(mockStream(1, 2, 3), mockStream(1, 4))
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	want, err := Diff(mockStream(1, 4), mockStream(1, 2, 3))
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+
+	got := Invert(d)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Invert() = %#v, expected %#v", got, want)
+	}
+}
+
+func TestInvertUnsupported(t *testing.T) {
+	for _, d := range []Differ{unsupportedDiffer{}, ignore{}} {
+		got := Invert(d)
+		if got != d {
+			t.Errorf("Invert(%#v) = %#v, expected %#v", d, got, d)
+		}
+	}
+}
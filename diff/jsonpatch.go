@@ -0,0 +1,368 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
+// Operations used in a JSON Patch.
+const (
+	PatchAdd     = "add"
+	PatchRemove  = "remove"
+	PatchReplace = "replace"
+	PatchMove    = "move"
+)
+
+// PatchOperation is a single operation of an RFC 6902 JSON Patch.
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	From  string      `json:"from,omitempty"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON omits the value for remove and move operations.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	switch o.Op {
+	case PatchRemove:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	case PatchMove:
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			From string `json:"from"`
+			Path string `json:"path"`
+		}{o.Op, o.From, o.Path})
+	}
+
+	type operation PatchOperation
+
+	return json.Marshal(operation(o))
+}
+
+var (
+	pointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	pointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// JSONPatch generates an RFC 6902 JSON Patch from a diff tree.
+// Applying the operations (in order) to the LHS produces the RHS.
+// Ignored nodes (see Ignore) do not produce any operation.
+func JSONPatch(d Differ) ([]PatchOperation, error) {
+	return jsonPatch([]PatchOperation{}, "", d)
+}
+
+func jsonPatch(ops []PatchOperation, pointer string, d Differ) ([]PatchOperation, error) {
+	if d.Diff() == Identical {
+		return ops, nil
+	}
+
+	switch d := d.(type) {
+	case scalar, types:
+		return append(ops, PatchOperation{Op: PatchReplace, Path: pointer, Value: d.(rhsGetter).RHS()}), nil
+	case mapDiff:
+		if d.Diff() == TypesDiffer {
+			return append(ops, PatchOperation{Op: PatchReplace, Path: pointer, Value: d.rhs}), nil
+		}
+		return mapPatch(ops, pointer, d)
+	case structDiff:
+		if d.Diff() == TypesDiffer {
+			return append(ops, PatchOperation{Op: PatchReplace, Path: pointer, Value: d.rhs}), nil
+		}
+		return structPatch(ops, pointer, d)
+	case slice:
+		if d.Diff() == TypesDiffer {
+			return append(ops, PatchOperation{Op: PatchReplace, Path: pointer, Value: d.rhs}), nil
+		}
+		return slicePatch(ops, pointer, d.diffs)
+	case stream:
+		return slicePatch(ops, pointer, d.diffs)
+	}
+
+	return ops, ErrDifferNotSupported{Diff: d}
+}
+
+func mapPatch(ops []PatchOperation, pointer string, m mapDiff) ([]PatchOperation, error) {
+	var err error
+
+	for _, key := range m.sortedKeys() {
+		ops, err = keyPatch(ops, pointer+"/"+pointerEscaper.Replace(fmt.Sprintf("%v", key)), m.diffs[key])
+		if err != nil {
+			return ops, err
+		}
+	}
+
+	return ops, nil
+}
+
+func structPatch(ops []PatchOperation, pointer string, s structDiff) ([]PatchOperation, error) {
+	var err error
+
+	for _, key := range s.sortedKeys() {
+		ops, err = keyPatch(ops, pointer+"/"+pointerEscaper.Replace(key), s.diffs[key])
+		if err != nil {
+			return ops, err
+		}
+	}
+
+	return ops, nil
+}
+
+func keyPatch(ops []PatchOperation, pointer string, d Differ) ([]PatchOperation, error) {
+	switch d := d.(type) {
+	case mapMissing:
+		return append(ops, PatchOperation{Op: PatchRemove, Path: pointer}), nil
+	case mapExcess:
+		return append(ops, PatchOperation{Op: PatchAdd, Path: pointer, Value: d.value}), nil
+	}
+
+	return jsonPatch(ops, pointer, d)
+}
+
+func slicePatch(ops []PatchOperation, pointer string, diffs []Differ) ([]PatchOperation, error) {
+	var (
+		err error
+		// ids identifies the elements of the partially patched slice by their position in diffs.
+		ids   []int
+		moved []int
+	)
+
+	// pos is the index of the current element in the partially patched slice.
+	pos := 0
+	for i, d := range diffs {
+		elPointer := pointer + "/" + strconv.Itoa(pos)
+
+		switch d := d.(type) {
+		case sliceMissing, streamMissing:
+			ops = append(ops, PatchOperation{Op: PatchRemove, Path: elPointer})
+			continue
+		case sliceMoved:
+			moved = append(moved, i)
+		case ignore:
+			// ignored excess elements are left out of the patched slice
+			if d.excess {
+				continue
+			}
+		case sliceExcess:
+			ops = append(ops, PatchOperation{Op: PatchAdd, Path: elPointer, Value: d.value})
+		case streamExcess:
+			ops = append(ops, PatchOperation{Op: PatchAdd, Path: elPointer, Value: d.value})
+		default:
+			ops, err = jsonPatch(ops, elPointer, d)
+			if err != nil {
+				return ops, err
+			}
+		}
+		ids = append(ids, i)
+		pos++
+	}
+
+	return movePatch(ops, pointer, diffs, ids, moved), nil
+}
+
+// movePatch appends the operations moving the moved elements (identified by their position in
+// diffs) to their RHS index, once the other operations are applied. The elements are moved in
+// the order of their RHS index, each one right after the element preceding it in the RHS.
+func movePatch(ops []PatchOperation, pointer string, diffs []Differ, ids, moved []int) []PatchOperation {
+	if len(moved) == 0 {
+		return ops
+	}
+	sort.Slice(moved, func(i, j int) bool {
+		return diffs[moved[i]].(sliceMoved).to < diffs[moved[j]].(sliceMoved).to
+	})
+
+	isMoved := make(map[int]bool, len(moved))
+	for _, id := range moved {
+		isMoved[id] = true
+	}
+	rhs := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !isMoved[id] {
+			rhs = append(rhs, id)
+		}
+	}
+	for _, id := range moved {
+		rhs = insertID(rhs, diffs[id].(sliceMoved).to, id)
+	}
+
+	for _, id := range moved {
+		from := indexOfID(ids, id)
+		ids = append(ids[:from], ids[from+1:]...)
+
+		to := 0
+		if i := indexOfID(rhs, id); i > 0 {
+			to = indexOfID(ids, rhs[i-1]) + 1
+		}
+		ids = insertID(ids, to, id)
+		if from == to {
+			continue
+		}
+		ops = append(ops, PatchOperation{
+			Op:   PatchMove,
+			From: pointer + "/" + strconv.Itoa(from),
+			Path: pointer + "/" + strconv.Itoa(to),
+		})
+	}
+
+	return ops
+}
+
+func insertID(ids []int, i, id int) []int {
+	if i > len(ids) {
+		i = len(ids)
+	}
+	ids = append(ids, 0)
+	copy(ids[i+1:], ids[i:])
+	ids[i] = id
+
+	return ids
+}
+
+func indexOfID(ids []int, id int) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ApplyJSONPatch applies the add, remove, replace and move operations of an RFC 6902 JSON Patch
+// (such as the ones generated by JSONPatch) to doc, a tree of map[string]interface{} and
+// []interface{} as decoded by encoding/json. Unlike Apply, it does not need the diff tree the
+// patch was generated from. The maps of doc are modified in place, and the patched document is
+// returned.
+func ApplyJSONPatch(doc interface{}, ops []PatchOperation) (interface{}, error) {
+	var err error
+
+	for i, op := range ops {
+		doc, err = applyPatchOperation(doc, op)
+		if err != nil {
+			return doc, fmt.Errorf("operation %d (%s %q): %v", i, op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+func applyPatchOperation(doc interface{}, op PatchOperation) (interface{}, error) {
+	switch op.Op {
+	case PatchAdd:
+		path, err := pointerPath(doc, op.Path, true)
+		if err != nil {
+			return doc, err
+		}
+		return jpath.InsertPath(path, doc, op.Value, false)
+	case PatchReplace:
+		path, err := pointerPath(doc, op.Path, false)
+		if err != nil {
+			return doc, err
+		}
+		return jpath.SetPath(path, doc, op.Value, false)
+	case PatchRemove:
+		path, err := pointerPath(doc, op.Path, false)
+		if err != nil {
+			return doc, err
+		}
+		if path == "" {
+			return doc, errors.New("cannot remove the root value")
+		}
+		return jpath.DeletePath(path, doc)
+	case PatchMove:
+		return applyMove(doc, op)
+	}
+
+	return doc, fmt.Errorf("unsupported operation %q", op.Op)
+}
+
+// applyMove applies a move operation as the removal of the value at op.From followed by its
+// addition at op.Path.
+func applyMove(doc interface{}, op PatchOperation) (interface{}, error) {
+	from, err := pointerPath(doc, op.From, false)
+	if err != nil {
+		return doc, err
+	}
+	if from == "" {
+		return doc, errors.New("cannot move the root value")
+	}
+	value, err := jpath.ExecutePath(from, doc)
+	if err != nil {
+		return doc, err
+	}
+	doc, err = jpath.DeletePath(from, doc)
+	if err != nil {
+		return doc, err
+	}
+
+	return applyPatchOperation(doc, PatchOperation{Op: PatchAdd, Path: op.Path, Value: value})
+}
+
+// pointerPath converts a JSON Pointer to a path of doc (see jpath.ExecutePath), checking that the
+// value it references exists. When add is true, the pointer can reference a missing map key or the
+// end of a slice ("-").
+func pointerPath(doc interface{}, pointer string, add bool) (string, error) {
+	if pointer == "" {
+		return "", nil
+	}
+	if pointer[0] != '/' {
+		return "", fmt.Errorf("invalid pointer %q", pointer)
+	}
+
+	var path strings.Builder
+	tokens := strings.Split(pointer[1:], "/")
+	v := doc
+	for i, token := range tokens {
+		token = pointerUnescaper.Replace(token)
+		last := add && i == len(tokens)-1
+
+		switch c := v.(type) {
+		case map[string]interface{}:
+			el, ok := c[token]
+			if !ok && !last {
+				return "", fmt.Errorf("key %q not found", token)
+			}
+			fmt.Fprintf(&path, "[%q]", token)
+			v = el
+		case []interface{}:
+			index, err := pointerIndex(token, len(c), last)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&path, "[%d]", index)
+			if index < len(c) {
+				v = c[index]
+			}
+		default:
+			return "", fmt.Errorf("cannot find %q in %T", token, v)
+		}
+	}
+
+	return path.String(), nil
+}
+
+// pointerIndex parses the index of an element of a slice of length l. When add is true, the index
+// can reference the end of the slice (either as l or as "-").
+func pointerIndex(token string, l int, add bool) (int, error) {
+	if token == "-" && add {
+		return l, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index %q", token)
+	}
+	if index < 0 || index > l || (index == l && !add) {
+		return 0, fmt.Errorf("index %q out of bounds", token)
+	}
+
+	return index, nil
+}
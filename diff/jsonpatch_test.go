@@ -0,0 +1,315 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestJSONPatch(t *testing.T) {
+	for _, test := range []struct {
+		LHS  interface{}
+		RHS  interface{}
+		Opts []ConfigOpt
+		Want []PatchOperation
+	}{
+		{LHS: 42, RHS: 42, Want: []PatchOperation{}},
+		{
+			LHS:  42,
+			RHS:  "42",
+			Want: []PatchOperation{{Op: PatchReplace, Path: "", Value: "42"}},
+		},
+		{
+			LHS: map[string]interface{}{"a": 1, "b": 2, "c/d": 3, "e~f": 4},
+			RHS: map[string]interface{}{"a": 1, "b": 3, "c/d": 3, "g": 5},
+			Want: []PatchOperation{
+				{Op: PatchReplace, Path: "/b", Value: 3},
+				{Op: PatchRemove, Path: "/e~0f"},
+				{Op: PatchAdd, Path: "/g", Value: 5},
+			},
+		},
+		{
+			LHS:  []interface{}{map[string]interface{}{"a": 1, "b": 2, "c": 3}, "x"},
+			RHS:  []interface{}{0, map[string]interface{}{"a": 1, "b": 2, "c": 7}},
+			Opts: []ConfigOpt{UseSliceMyers()},
+			Want: []PatchOperation{
+				{Op: PatchAdd, Path: "/0", Value: 0},
+				{Op: PatchReplace, Path: "/1/c", Value: 7},
+				{Op: PatchRemove, Path: "/2"},
+			},
+		},
+		{
+			LHS: []interface{}{1, 2, 3},
+			RHS: []interface{}{1, 4},
+			Want: []PatchOperation{
+				{Op: PatchReplace, Path: "/1", Value: 4},
+				{Op: PatchRemove, Path: "/2"},
+			},
+		},
+		{
+			LHS:  []interface{}{1, 2, 3},
+			RHS:  []interface{}{0, 1, 2, 5},
+			Opts: []ConfigOpt{UseSliceMyers()},
+			Want: []PatchOperation{
+				{Op: PatchAdd, Path: "/0", Value: 0},
+				{Op: PatchRemove, Path: "/3"},
+				{Op: PatchAdd, Path: "/3", Value: 5},
+			},
+		},
+		{
+			LHS:  []interface{}{1, 2, 3, 4},
+			RHS:  []interface{}{4, 2, 5, 3, 1},
+			Opts: []ConfigOpt{UseSliceMyers()},
+			Want: []PatchOperation{
+				{Op: PatchAdd, Path: "/2", Value: 5},
+				{Op: PatchMove, From: "/4", Path: "/0"},
+				{Op: PatchMove, From: "/1", Path: "/4"},
+			},
+		},
+		{
+			LHS: map[string]interface{}{"a": []interface{}{1}},
+			RHS: map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b/c": nil}}},
+			Want: []PatchOperation{
+				{Op: PatchAdd, Path: "/a/1", Value: map[string]interface{}{"b/c": nil}},
+			},
+		},
+		{
+			LHS:  map[string]interface{}{"a": nil},
+			RHS:  map[string]interface{}{"a": 1},
+			Want: []PatchOperation{{Op: PatchReplace, Path: "/a", Value: 1}},
+		},
+		{
+			LHS:  structA{Foo: 1},
+			RHS:  structA{Foo: 2},
+			Want: []PatchOperation{{Op: PatchReplace, Path: "/Foo", Value: 2}},
+		},
+		{
+			LHS: mockStream(1, 2),
+			RHS: mockStream(1, 3, 4),
+			Want: []PatchOperation{
+				{Op: PatchReplace, Path: "/1", Value: 3},
+				{Op: PatchAdd, Path: "/2", Value: 4},
+			},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		got, err := JSONPatch(d)
+		if err != nil {
+			t.Errorf("JSONPatch(Diff(%+v, %+v)): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("JSONPatch(Diff(%+v, %+v)) = %+v, expected %+v", test.LHS, test.RHS, got, test.Want)
+		}
+	}
+}
+
+func TestJSONPatchIgnore(t *testing.T) {
+	d, err := Diff(map[string]int{"a": 1, "b": 2}, map[string]int{"a": 2, "b": 3})
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	d, err = Walk(d, func(_, _ Differ, path string) (Differ, error) {
+		if path == ".a" {
+			return Ignore()
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %s", err)
+	}
+
+	got, err := JSONPatch(d)
+	if err != nil {
+		t.Fatalf("JSONPatch: unexpected error: %s", err)
+	}
+	want := []PatchOperation{{Op: PatchReplace, Path: "/b", Value: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONPatch() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestJSONPatchIgnoreExcess(t *testing.T) {
+	d, err := Diff([]int{1, 2}, []int{0, 1, 3}, UseSliceMyers())
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	d, err = Walk(d, func(_, d Differ, _ string) (Differ, error) {
+		if IsExcess(d) {
+			return Ignore()
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %s", err)
+	}
+
+	got, err := JSONPatch(d)
+	if err != nil {
+		t.Fatalf("JSONPatch: unexpected error: %s", err)
+	}
+	want := []PatchOperation{{Op: PatchRemove, Path: "/1"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JSONPatch() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	doc := func() interface{} {
+		return map[string]interface{}{
+			"a": []interface{}{1.0, 2.0, 3.0},
+			"b": map[string]interface{}{"c/d": "e"},
+		}
+	}
+
+	for _, test := range []struct {
+		Name string
+		Ops  []PatchOperation
+		Want interface{}
+	}{
+		{
+			Name: "add index",
+			Ops:  []PatchOperation{{Op: PatchAdd, Path: "/a/1", Value: 4.0}},
+			Want: map[string]interface{}{
+				"a": []interface{}{1.0, 4.0, 2.0, 3.0},
+				"b": map[string]interface{}{"c/d": "e"},
+			},
+		},
+		{
+			Name: "add -",
+			Ops:  []PatchOperation{{Op: PatchAdd, Path: "/a/-", Value: 4.0}},
+			Want: map[string]interface{}{
+				"a": []interface{}{1.0, 2.0, 3.0, 4.0},
+				"b": map[string]interface{}{"c/d": "e"},
+			},
+		},
+		{
+			Name: "replace escaped key",
+			Ops:  []PatchOperation{{Op: PatchReplace, Path: "/b/c~1d", Value: "f"}},
+			Want: map[string]interface{}{
+				"a": []interface{}{1.0, 2.0, 3.0},
+				"b": map[string]interface{}{"c/d": "f"},
+			},
+		},
+		{
+			Name: "remove",
+			Ops:  []PatchOperation{{Op: PatchRemove, Path: "/a/0"}, {Op: PatchRemove, Path: "/b"}},
+			Want: map[string]interface{}{
+				"a": []interface{}{2.0, 3.0},
+			},
+		},
+		{
+			Name: "move",
+			Ops:  []PatchOperation{{Op: PatchMove, From: "/a/0", Path: "/a/2"}},
+			Want: map[string]interface{}{
+				"a": []interface{}{2.0, 3.0, 1.0},
+				"b": map[string]interface{}{"c/d": "e"},
+			},
+		},
+		{
+			Name: "move to map",
+			Ops:  []PatchOperation{{Op: PatchMove, From: "/b/c~1d", Path: "/g"}},
+			Want: map[string]interface{}{
+				"a": []interface{}{1.0, 2.0, 3.0},
+				"b": map[string]interface{}{},
+				"g": "e",
+			},
+		},
+		{
+			Name: "replace root",
+			Ops:  []PatchOperation{{Op: PatchReplace, Path: "", Value: 42.0}},
+			Want: 42.0,
+		},
+	} {
+		got, err := ApplyJSONPatch(doc(), test.Ops)
+		if err != nil {
+			t.Errorf("%s: ApplyJSONPatch(%+v): unexpected error: %s", test.Name, test.Ops, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("%s: ApplyJSONPatch(%+v) = %+v, expected %+v", test.Name, test.Ops, got, test.Want)
+		}
+	}
+}
+
+func TestApplyJSONPatchErrors(t *testing.T) {
+	for _, test := range []struct {
+		Name string
+		Op   PatchOperation
+	}{
+		{Name: "add out of bounds", Op: PatchOperation{Op: PatchAdd, Path: "/a/4", Value: 4.0}},
+		{Name: "add negative index", Op: PatchOperation{Op: PatchAdd, Path: "/a/-1", Value: 4.0}},
+		{Name: "replace out of bounds", Op: PatchOperation{Op: PatchReplace, Path: "/a/3", Value: 4.0}},
+		{Name: "remove out of bounds", Op: PatchOperation{Op: PatchRemove, Path: "/a/3"}},
+		{Name: "invalid index", Op: PatchOperation{Op: PatchReplace, Path: "/a/x", Value: 4.0}},
+		{Name: "replace -", Op: PatchOperation{Op: PatchReplace, Path: "/a/-", Value: 4.0}},
+		{Name: "remove -", Op: PatchOperation{Op: PatchRemove, Path: "/a/-"}},
+		{Name: "traverse -", Op: PatchOperation{Op: PatchAdd, Path: "/a/-/b", Value: 4.0}},
+		{Name: "replace missing key", Op: PatchOperation{Op: PatchReplace, Path: "/c", Value: 4.0}},
+		{Name: "remove missing key", Op: PatchOperation{Op: PatchRemove, Path: "/b/d"}},
+		{Name: "add to missing key", Op: PatchOperation{Op: PatchAdd, Path: "/c/d", Value: 4.0}},
+		{Name: "add to scalar", Op: PatchOperation{Op: PatchAdd, Path: "/a/0/b", Value: 4.0}},
+		{Name: "remove root", Op: PatchOperation{Op: PatchRemove, Path: ""}},
+		{Name: "unsupported operation", Op: PatchOperation{Op: "copy", Path: "/c", From: "/b"}},
+		{Name: "move missing key", Op: PatchOperation{Op: PatchMove, From: "/c", Path: "/d"}},
+		{Name: "move out of bounds", Op: PatchOperation{Op: PatchMove, From: "/a/3", Path: "/a/0"}},
+		{Name: "move to out of bounds", Op: PatchOperation{Op: PatchMove, From: "/a/0", Path: "/a/3"}},
+	} {
+		doc := map[string]interface{}{
+			"a": []interface{}{1.0, 2.0, 3.0},
+			"b": map[string]interface{}{"c": "e"},
+		}
+
+		_, err := ApplyJSONPatch(doc, []PatchOperation{test.Op})
+		if err == nil {
+			t.Errorf("%s: ApplyJSONPatch(%+v): expected an error, got nil", test.Name, test.Op)
+		}
+	}
+}
+
+func TestJSONPatchUnsupported(t *testing.T) {
+	_, err := JSONPatch(unsupportedDiffer{})
+	if _, ok := err.(ErrDifferNotSupported); !ok {
+		t.Errorf("JSONPatch(unsupportedDiffer{}): expected ErrDifferNotSupported, got %v", err)
+	}
+}
+
+func TestPatchOperationMarshalJSON(t *testing.T) {
+	for _, test := range []struct {
+		Op   PatchOperation
+		Want string
+	}{
+		{PatchOperation{Op: PatchRemove, Path: "/a"}, `{"op":"remove","path":"/a"}`},
+		{PatchOperation{Op: PatchAdd, Path: "/a", Value: nil}, `{"op":"add","path":"/a","value":null}`},
+		{PatchOperation{Op: PatchReplace, Path: "", Value: 42}, `{"op":"replace","path":"","value":42}`},
+		{PatchOperation{Op: PatchMove, From: "/0", Path: "/2"}, `{"op":"move","from":"/0","path":"/2"}`},
+	} {
+		b, err := json.Marshal(test.Op)
+		if err != nil {
+			t.Errorf("json.Marshal(%+v): unexpected error: %s", test.Op, err)
+			continue
+		}
+		if string(b) != test.Want {
+			t.Errorf("json.Marshal(%+v) = %s, expected %s", test.Op, b, test.Want)
+		}
+	}
+}
+
+type unsupportedDiffer struct{}
+
+func (unsupportedDiffer) Diff() Type {
+	return ContentDiffer
+}
+
+func (unsupportedDiffer) Strings() []string {
+	return nil
+}
+
+func (unsupportedDiffer) StringIndent(key, prefix string, conf Output) string {
+	return ""
+}
@@ -0,0 +1,100 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
+// newKeyedSlice matches the elements of two slices of maps using the values of the given keys
+// (see KeyedSlices and SliceKey). ok is false if the elements cannot be matched this way
+// (i.e the elements are not maps or a key is missing from one of them).
+func newKeyedSlice(c config, keys []string, lhs, rhs interface{}, visited *visited) (d Differ, ok bool, err error) {
+	if typesDiffer, err := sliceTypesDiffer(lhs, rhs); err != nil || typesDiffer {
+		return nil, false, nil
+	}
+
+	lhsVal := reflect.ValueOf(lhs)
+	rhsVal := reflect.ValueOf(rhs)
+
+	lhsKeys, ok := sliceElementKeys(lhsVal, keys)
+	if !ok {
+		return nil, false, nil
+	}
+	rhsKeys, ok := sliceElementKeys(rhsVal, keys)
+	if !ok {
+		return nil, false, nil
+	}
+
+	rhsByKey := make(map[string][]int, len(rhsKeys))
+	for j, key := range rhsKeys {
+		rhsByKey[key] = append(rhsByKey[key], j)
+	}
+
+	s := slice{
+		lhs: lhs,
+		rhs: rhs,
+	}
+	matched := make([]bool, len(rhsKeys))
+	for i, key := range lhsKeys {
+		s.indices = append(s.indices, i)
+		s.labels = append(s.labels, key)
+
+		if len(rhsByKey[key]) == 0 {
+			s.pairs = append(s.pairs, -1)
+			s.diffs = append(s.diffs, sliceMissing{lhsVal.Index(i).Interface()})
+			continue
+		}
+		j := rhsByKey[key][0]
+		rhsByKey[key] = rhsByKey[key][1:]
+		matched[j] = true
+		s.pairs = append(s.pairs, j)
+
+		d, err := diff(c.at("["+key+"]"), lhsVal.Index(i).Interface(), rhsVal.Index(j).Interface(), visited)
+		s.diffs = append(s.diffs, d)
+		if err != nil {
+			return s, true, err
+		}
+	}
+	for j, key := range rhsKeys {
+		if matched[j] {
+			continue
+		}
+		s.indices = append(s.indices, j)
+		s.labels = append(s.labels, key)
+		s.pairs = append(s.pairs, j)
+		s.diffs = append(s.diffs, sliceExcess{rhsVal.Index(j).Interface()})
+	}
+
+	return s, true, nil
+}
+
+// sliceElementKeys returns the labels identifying each element of a slice of maps,
+// in the form `key1=value1,key2=value2`.
+func sliceElementKeys(v reflect.Value, keys []string) ([]string, bool) {
+	labels := make([]string, v.Len())
+
+	for i := 0; i < v.Len(); i++ {
+		el := v.Index(i)
+		if el.Kind() == reflect.Interface {
+			el = el.Elem()
+		}
+		if el.Kind() != reflect.Map || el.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		parts := make([]string, len(keys))
+		for k, key := range keys {
+			val := el.MapIndex(reflect.ValueOf(key).Convert(el.Type().Key()))
+			if !val.IsValid() || !val.CanInterface() {
+				return nil, false
+			}
+			parts[k] = key + "=" + jpath.EscapeKey(fmt.Sprintf("%v", val.Interface()))
+		}
+		labels[i] = strings.Join(parts, ",")
+	}
+
+	return labels, true
+}
@@ -0,0 +1,268 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyedSlices(t *testing.T) {
+	row := func(id, v interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": id, "v": v}
+	}
+
+	for _, test := range []struct {
+		LHS   interface{}
+		RHS   interface{}
+		Keys  []string
+		Want  Type
+		Paths []string
+	}{
+		{
+			LHS:  []interface{}{row(1, "a"), row(2, "b")},
+			RHS:  []interface{}{row(2, "b"), row(1, "a")},
+			Keys: []string{"id"},
+			Want: Identical,
+		},
+		{
+			LHS:   []interface{}{row(1, "a"), row(2, "b"), row(3, "c")},
+			RHS:   []interface{}{row(4, "d"), row(2, "e"), row(1, "a")},
+			Keys:  []string{"id"},
+			Want:  ContentDiffer,
+			Paths: []string{"[id=2].v", "[id=3]", "[id=4]"},
+		},
+		{
+			LHS:   []interface{}{row(1, "a"), row(1, "b")},
+			RHS:   []interface{}{row(1, "b"), row(1, "a")},
+			Keys:  []string{"id", "v"},
+			Want:  Identical,
+			Paths: nil,
+		},
+		{
+			LHS:   []map[string]string{{"id": "a.b", "v": "1"}},
+			RHS:   []map[string]string{{"id": "a.b", "v": "2"}},
+			Keys:  []string{"id"},
+			Want:  ContentDiffer,
+			Paths: []string{`[id="a.b"].v`},
+		},
+		{
+			LHS:   []interface{}{row(1, "a"), 2},
+			RHS:   []interface{}{row(1, "b"), 2},
+			Keys:  []string{"id"},
+			Want:  ContentDiffer,
+			Paths: []string{"[0].v"},
+		},
+		{
+			LHS:   []interface{}{row(1, "a")},
+			RHS:   []interface{}{map[string]interface{}{"v": "b"}},
+			Keys:  []string{"id"},
+			Want:  ContentDiffer,
+			Paths: []string{"[0].id", "[0].v"},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, KeyedSlices(test.Keys...))
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v, KeyedSlices(%q)): unexpected error: %s", test.LHS, test.RHS, test.Keys, err)
+			continue
+		}
+		if d.Diff() != test.Want {
+			t.Errorf("Diff(%+v, %+v, KeyedSlices(%q)) = %s, expected %s", test.LHS, test.RHS, test.Keys, d.Diff(), test.Want)
+		}
+
+		got, err := changedPaths(d)
+		if err != nil {
+			t.Errorf("Walk(Diff(%+v, %+v, KeyedSlices(%q))): unexpected error: %s", test.LHS, test.RHS, test.Keys, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf(
+				"Diff(%+v, %+v, KeyedSlices(%q)): got paths %q, expected %q",
+				test.LHS, test.RHS, test.Keys, got, test.Paths,
+			)
+		}
+	}
+}
+
+func TestSliceKey(t *testing.T) {
+	user := func(id, name interface{}) map[string]interface{} {
+		return map[string]interface{}{"id": id, "name": name}
+	}
+	lhs := map[string]interface{}{
+		"users": []interface{}{user(1, "alice"), user(2, "bob")},
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":    "admins",
+				"members": []interface{}{user(1, "alice")},
+			},
+		},
+		"admins": []interface{}{user(1, "alice"), user(2, "bob")},
+	}
+	rhs := map[string]interface{}{
+		"users": []interface{}{user(3, "carol"), user(2, "bob"), user(1, "alice")},
+		"groups": []interface{}{
+			map[string]interface{}{
+				"name":    "admins",
+				"members": []interface{}{user(3, "carol"), user(1, "alicia")},
+			},
+		},
+		"admins": []interface{}{user(2, "bob"), user(1, "alice")},
+	}
+
+	for _, test := range []struct {
+		Opts  []ConfigOpt
+		Paths []string
+	}{
+		{
+			Opts: []ConfigOpt{SliceKey(".users[]", "id")},
+			Paths: []string{
+				".admins[0].id", ".admins[0].name", ".admins[1].id", ".admins[1].name",
+				".groups[0].members[0].id", ".groups[0].members[0].name", ".groups[0].members[1]",
+				".users[id=3]",
+			},
+		},
+		{
+			Opts: []ConfigOpt{SliceKey(".users", "id"), SliceKey(".groups[].members[]", "id")},
+			Paths: []string{
+				".admins[0].id", ".admins[0].name", ".admins[1].id", ".admins[1].name",
+				".groups[0].members[id=1].name", ".groups[0].members[id=3]",
+				".users[id=3]",
+			},
+		},
+		{
+			Opts:  []ConfigOpt{SliceKey("*", "id")},
+			Paths: []string{".groups[0].members[id=1].name", ".groups[0].members[id=3]", ".users[id=3]"},
+		},
+		{
+			Opts: []ConfigOpt{KeyedSlices("name"), SliceKey(".u*[]", "id")},
+			Paths: []string{
+				".groups[name=admins].members[name=alice]",
+				".groups[name=admins].members[name=carol]",
+				".groups[name=admins].members[name=alicia]",
+				".users[id=3]",
+			},
+		},
+	} {
+		d, err := Diff(lhs, rhs, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(): unexpected error: %s", err)
+			continue
+		}
+		got, err := changedPaths(d)
+		if err != nil {
+			t.Errorf("Walk(Diff()): unexpected error: %s", err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf("Diff(): got paths %q, expected %q", got, test.Paths)
+		}
+	}
+
+	_, err := Diff(lhs, rhs, SliceKey(".users[", "id"))
+	if err == nil {
+		t.Error("Diff(SliceKey(\".users[\")): expected error, got nil")
+	}
+}
+
+func changedPaths(d Differ) ([]string, error) {
+	var paths []string
+
+	_, err := Walk(d, func(_, d Differ, path string) (Differ, error) {
+		if _, ok := d.(Walker); !ok && d.Diff() != Identical {
+			paths = append(paths, path)
+		}
+		return nil, nil
+	})
+
+	return paths, err
+}
+
+func TestKeyedSlicesApply(t *testing.T) {
+	lhs := []interface{}{
+		map[string]interface{}{"id": 1, "v": "a"},
+		map[string]interface{}{"id": 2, "v": "b"},
+	}
+	rhs := []interface{}{
+		map[string]interface{}{"id": 3, "v": "c"},
+		map[string]interface{}{"id": 1, "v": "d"},
+	}
+	want := []interface{}{
+		map[string]interface{}{"id": 1, "v": "d"},
+		map[string]interface{}{"id": 3, "v": "c"},
+	}
+
+	d, err := Diff(lhs, rhs, KeyedSlices("id"))
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	got, err := Apply(d, lhs)
+	if err != nil {
+		t.Fatalf("Apply: unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, expected %+v", got, want)
+	}
+
+}
+
+func TestKeyedSlicesInvert(t *testing.T) {
+	for _, test := range []struct {
+		LHS  interface{}
+		RHS  interface{}
+		Opts []ConfigOpt
+	}{
+		{
+			LHS: []interface{}{
+				map[string]interface{}{"id": 1, "v": "a"},
+				map[string]interface{}{"id": 2, "v": "b"},
+			},
+			RHS: []interface{}{
+				map[string]interface{}{"id": 3, "v": "c"},
+				map[string]interface{}{"id": 1, "v": "d"},
+			},
+			Opts: []ConfigOpt{KeyedSlices("id")},
+		},
+		{
+			LHS: []interface{}{
+				map[string]interface{}{"id": 4, "v": 0},
+			},
+			RHS: []interface{}{
+				map[string]interface{}{"id": 5, "v": 0},
+				map[string]interface{}{"id": 4, "v": 1},
+			},
+			Opts: []ConfigOpt{KeyedSlices("id")},
+		},
+		{
+			LHS: map[string]interface{}{"users": []interface{}{
+				map[string]interface{}{"id": 1, "v": "a"},
+				map[string]interface{}{"id": 2, "v": "b"},
+				map[string]interface{}{"id": 3, "v": "c"},
+			}},
+			RHS: map[string]interface{}{"users": []interface{}{
+				map[string]interface{}{"id": 3, "v": "c"},
+				map[string]interface{}{"id": 4, "v": "d"},
+				map[string]interface{}{"id": 2, "v": "e"},
+				map[string]interface{}{"id": 1, "v": "a"},
+			}},
+			Opts: []ConfigOpt{SliceKey(".users", "id")},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		got, err := Apply(Invert(d), test.RHS)
+		if err != nil {
+			t.Errorf("Apply(Invert(Diff(%+v, %+v))): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		check, err := Diff(got, test.LHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", got, test.LHS, err)
+			continue
+		}
+		if check.Diff() != Identical {
+			t.Errorf("Apply(Invert(Diff(%+v, %+v))) = %+v, expected %+v", test.LHS, test.RHS, got, test.LHS)
+		}
+	}
+}
@@ -43,7 +43,7 @@ func newMap(c config, lhs, rhs interface{}, visited *visited) (Differ, error) {
 			rhsEl := rhsVal.MapIndex(key)
 
 			if lhsEl.IsValid() && rhsEl.IsValid() {
-				diff, err := diff(c, lhsEl.Interface(), rhsEl.Interface(), visited)
+				diff, err := diff(c.at("."+jpath.EscapeKey(key.Interface())), lhsEl.Interface(), rhsEl.Interface(), visited)
 				diffs[key.Interface()] = diff
 
 				if err != nil {
@@ -115,17 +115,8 @@ func (m mapDiff) Strings() []string {
 		}
 	case ContentDiffer:
 		var ss = []string{"{"}
-		keys := make([]interface{}, 0, len(m.diffs))
 
-		for key := range m.diffs {
-			keys = append(keys, key)
-		}
-
-		sort.Slice(keys, func(i, j int) bool {
-			return strings.Compare(fmt.Sprintf("%v", keys[i]), fmt.Sprintf("%v", keys[j])) == -1
-		})
-
-		for _, key := range keys {
+		for _, key := range m.sortedKeys() {
 			d := m.diffs[key]
 			for _, s := range d.Strings() {
 				ss = append(ss, fmt.Sprintf("%v: %s", key, s))
@@ -147,17 +138,8 @@ func (m mapDiff) StringIndent(keyprefix, prefix string, conf Output) string {
 			"+" + prefix + keyprefix + conf.green(m.rhs)
 	case ContentDiffer:
 		var ss = []string{}
-		keys := make([]interface{}, 0, len(m.diffs))
-
-		for key := range m.diffs {
-			keys = append(keys, key)
-		}
-
-		sort.Slice(keys, func(i, j int) bool {
-			return strings.Compare(fmt.Sprintf("%v", keys[i]), fmt.Sprintf("%v", keys[j])) == -1
-		})
 
-		for _, key := range keys {
+		for _, key := range m.sortedKeys() {
 			d := m.diffs[key]
 
 			keyStr := m.mapKeyString(key, conf)
@@ -200,19 +182,12 @@ func (m mapDiff) mapKeyString(key interface{}, conf Output) string {
 }
 
 func (m mapDiff) Walk(path string, fn WalkFn) error {
-	keys := make([]interface{}, 0, len(m.diffs))
-
-	for k := range m.diffs {
-		keys = append(keys, k)
-	}
-
-	sort.Slice(keys, func(i, j int) bool {
-		return strings.Compare(fmt.Sprintf("%v", keys[i]), fmt.Sprintf("%v", keys[j])) == -1
-	})
+	return m.walkElements(walkString(m, path, fn))
+}
 
-	for _, k := range keys {
-		diff := m.diffs[k]
-		d, err := walk(m, diff, path+"."+jpath.EscapeKey(k), fn)
+func (m mapDiff) walkElements(fn func(PathElement, Differ) (Differ, error)) error {
+	for _, k := range m.sortedKeys() {
+		d, err := fn(PathElement{Kind: KeyElement, Key: k}, m.diffs[k])
 		if err != nil {
 			return err
 		}
@@ -224,6 +199,20 @@ func (m mapDiff) Walk(path string, fn WalkFn) error {
 	return nil
 }
 
+func (m mapDiff) sortedKeys() []interface{} {
+	keys := make([]interface{}, 0, len(m.diffs))
+
+	for k := range m.diffs {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Compare(fmt.Sprintf("%v", keys[i]), fmt.Sprintf("%v", keys[j])) == -1
+	})
+
+	return keys
+}
+
 func (m mapDiff) LHS() interface{} {
 	return m.lhs
 }
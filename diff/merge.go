@@ -0,0 +1,264 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
+// Merge performs a three-way merge of ours and theirs, using base as their common ancestor.
+//
+// Changes made on only one side are applied to base, and changes made on both sides are
+// merged recursively (through maps, slices and structs). When both sides changed the same
+// key or slice index differently, its path is added to conflicts and the value from ours is used.
+func Merge(base, ours, theirs interface{}) (merged interface{}, conflicts []string, err error) {
+	oursDiff, err := Diff(base, ours)
+	if err != nil {
+		return nil, nil, err
+	}
+	theirsDiff, err := Diff(base, theirs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &merger{}
+	merged, err = m.merge("", base, oursDiff, theirsDiff)
+
+	return merged, m.conflicts, err
+}
+
+type merger struct {
+	conflicts []string
+}
+
+func (m *merger) merge(path string, base interface{}, ours, theirs Differ) (interface{}, error) {
+	switch {
+	case isUnchanged(ours):
+		return Apply(theirs, base)
+	case isUnchanged(theirs):
+		return Apply(ours, base)
+	case ours.Diff() == ContentDiffer && theirs.Diff() == ContentDiffer:
+		switch o := ours.(type) {
+		case mapDiff:
+			if t, ok := theirs.(mapDiff); ok {
+				return m.mergeMap(path, base, o, t)
+			}
+		case structDiff:
+			if t, ok := theirs.(structDiff); ok {
+				return m.mergeStruct(path, base, o, t)
+			}
+		case slice:
+			if t, ok := theirs.(slice); ok {
+				return m.mergeSlice(path, base, o, t)
+			}
+		}
+	}
+
+	v, _, err := m.mergeValues(path, base, true, ours, theirs)
+
+	return v, err
+}
+
+// mergeValues compares the results of applying ours and theirs to base, reporting a conflict
+// if they differ. keep is false if the value was removed.
+func (m *merger) mergeValues(path string, base interface{}, hasBase bool, ours, theirs Differ) (v interface{}, keep bool, err error) {
+	oursVal, oursKeep, err := mergeResult(base, hasBase, ours)
+	if err != nil {
+		return nil, false, err
+	}
+	theirsVal, theirsKeep, err := mergeResult(base, hasBase, theirs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if oursKeep != theirsKeep || !reflect.DeepEqual(oursVal, theirsVal) {
+		m.conflicts = append(m.conflicts, path)
+	}
+
+	return oursVal, oursKeep, nil
+}
+
+// mergeElement merges a map value, slice element or struct field.
+// A nil Differ is used when a side doesn't contain the element.
+func (m *merger) mergeElement(path string, base interface{}, hasBase bool, ours, theirs Differ) (v interface{}, keep bool, err error) {
+	switch {
+	case isUnchanged(ours):
+		return mergeResult(base, hasBase, theirs)
+	case isUnchanged(theirs):
+		return mergeResult(base, hasBase, ours)
+	case IsMissing(ours) || IsExcess(ours) || IsMissing(theirs) || IsExcess(theirs):
+		return m.mergeValues(path, base, hasBase, ours, theirs)
+	}
+
+	v, err = m.merge(path, base, ours, theirs)
+
+	return v, true, err
+}
+
+func (m *merger) mergeMap(path string, base interface{}, ours, theirs mapDiff) (interface{}, error) {
+	baseVal := reflect.Indirect(reflect.ValueOf(base))
+	if baseVal.Kind() != reflect.Map {
+		return base, errLHSMismatch{Value: base}
+	}
+
+	res := reflect.MakeMapWithSize(baseVal.Type(), baseVal.Len())
+	for _, key := range baseVal.MapKeys() {
+		res.SetMapIndex(key, baseVal.MapIndex(key))
+	}
+
+	for _, key := range mergeKeys(ours.diffs, theirs.diffs) {
+		var baseEl interface{}
+
+		keyVal := reflect.ValueOf(key)
+		baseElVal := baseVal.MapIndex(keyVal)
+		if baseElVal.IsValid() {
+			baseEl = baseElVal.Interface()
+		}
+
+		v, keep, err := m.mergeElement(
+			path+"."+jpath.EscapeKey(key), baseEl, baseElVal.IsValid(), ours.diffs[key], theirs.diffs[key],
+		)
+		if err != nil {
+			return base, err
+		}
+		if !keep {
+			res.SetMapIndex(keyVal, reflect.Value{})
+			continue
+		}
+		val, err := valueOfType(baseVal.Type().Elem(), v)
+		if err != nil {
+			return base, err
+		}
+		res.SetMapIndex(keyVal, val)
+	}
+
+	return res.Interface(), nil
+}
+
+func (m *merger) mergeStruct(path string, base interface{}, ours, theirs structDiff) (interface{}, error) {
+	baseVal := reflect.Indirect(reflect.ValueOf(base))
+	if baseVal.Kind() != reflect.Struct {
+		return base, errLHSMismatch{Value: base}
+	}
+
+	res := reflect.New(baseVal.Type()).Elem()
+	res.Set(baseVal)
+
+	for _, name := range ours.sortedKeys() {
+		field := res.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			return base, errLHSMismatch{Value: base}
+		}
+
+		v, _, err := m.mergeElement(path+"."+jpath.EscapeKey(name), field.Interface(), true, ours.diffs[name], theirs.diffs[name])
+		if err != nil {
+			return base, err
+		}
+		val, err := valueOfType(field.Type(), v)
+		if err != nil {
+			return base, err
+		}
+		field.Set(val)
+	}
+
+	return res.Interface(), nil
+}
+
+// mergeSlice merges slices element by element. It expects both diffs to be positional
+// (i.e created using the default slice algorithm).
+func (m *merger) mergeSlice(path string, base interface{}, ours, theirs slice) (interface{}, error) {
+	baseVal := reflect.Indirect(reflect.ValueOf(base))
+	if baseVal.Kind() != reflect.Slice && baseVal.Kind() != reflect.Array {
+		return base, errLHSMismatch{Value: base}
+	}
+	elType := baseVal.Type().Elem()
+
+	n := len(ours.diffs)
+	if len(theirs.diffs) > n {
+		n = len(theirs.diffs)
+	}
+
+	res := reflect.MakeSlice(reflect.SliceOf(elType), 0, n)
+	for i := 0; i < n; i++ {
+		var baseEl interface{}
+
+		hasBase := i < baseVal.Len()
+		if hasBase {
+			baseEl = baseVal.Index(i).Interface()
+		}
+
+		v, keep, err := m.mergeElement(
+			path+"["+strconv.Itoa(i)+"]", baseEl, hasBase, sliceElement(ours, i), sliceElement(theirs, i),
+		)
+		if err != nil {
+			return base, err
+		}
+		if !keep {
+			continue
+		}
+		val, err := valueOfType(elType, v)
+		if err != nil {
+			return base, err
+		}
+		res = reflect.Append(res, val)
+	}
+
+	if baseVal.Kind() == reflect.Array {
+		return sliceToArray(res, baseVal.Type())
+	}
+
+	return res.Interface(), nil
+}
+
+func sliceElement(s slice, i int) Differ {
+	if i >= len(s.diffs) {
+		return nil
+	}
+
+	return s.diffs[i]
+}
+
+func isUnchanged(d Differ) bool {
+	return d == nil || d.Diff() == Identical
+}
+
+// mergeResult returns the value produced by a single side of the merge.
+func mergeResult(base interface{}, hasBase bool, d Differ) (v interface{}, keep bool, err error) {
+	switch d := d.(type) {
+	case nil:
+		return base, hasBase, nil
+	case mapMissing, sliceMissing:
+		return nil, false, nil
+	case mapExcess:
+		return d.value, true, nil
+	case sliceExcess:
+		return d.value, true, nil
+	}
+
+	v, err = Apply(d, base)
+
+	return v, true, err
+}
+
+func mergeKeys(lhs, rhs map[interface{}]Differ) []interface{} {
+	keys := make([]interface{}, 0, len(lhs))
+
+	for k := range lhs {
+		keys = append(keys, k)
+	}
+	for k := range rhs {
+		if _, ok := lhs[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Compare(fmt.Sprintf("%v", keys[i]), fmt.Sprintf("%v", keys[j])) == -1
+	})
+
+	return keys
+}
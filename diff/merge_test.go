@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	for _, test := range []struct {
+		Base      interface{}
+		Ours      interface{}
+		Theirs    interface{}
+		Want      interface{}
+		Conflicts []string
+	}{
+		{Base: 1, Ours: 1, Theirs: 1, Want: 1},
+		{Base: 1, Ours: 2, Theirs: 1, Want: 2},
+		{Base: 1, Ours: 1, Theirs: 3, Want: 3},
+		{Base: 1, Ours: 2, Theirs: 2, Want: 2},
+		{Base: 1, Ours: 2, Theirs: 3, Want: 2, Conflicts: []string{""}},
+		{
+			Base:   map[string]interface{}{"a": 1, "b": 2, "c": 3},
+			Ours:   map[string]interface{}{"a": 4, "b": 2, "c": 3},
+			Theirs: map[string]interface{}{"a": 1, "b": 5},
+			Want:   map[string]interface{}{"a": 4, "b": 5},
+		},
+		{
+			Base:   map[string]interface{}{"a": 1},
+			Ours:   map[string]interface{}{"a": 1, "b": 2},
+			Theirs: map[string]interface{}{"a": 1, "c": 3},
+			Want:   map[string]interface{}{"a": 1, "b": 2, "c": 3},
+		},
+		{
+			Base:      map[string]interface{}{"a": 1, "b": 2},
+			Ours:      map[string]interface{}{"a": 2, "c": 3},
+			Theirs:    map[string]interface{}{"a": 3, "b": 4, "c": 4},
+			Want:      map[string]interface{}{"a": 2, "c": 3},
+			Conflicts: []string{".a", ".b", ".c"},
+		},
+		{
+			Base: map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}},
+			Ours: map[string]interface{}{"a": map[string]interface{}{"b": 3, "c": 2}},
+			Theirs: map[string]interface{}{
+				"a": map[string]interface{}{"b": 1, "c": 4},
+			},
+			Want: map[string]interface{}{"a": map[string]interface{}{"b": 3, "c": 4}},
+		},
+		{
+			Base:   map[string]interface{}{"a": map[string]interface{}{"b": 1}},
+			Ours:   map[string]interface{}{"a": map[string]interface{}{"b": 3}},
+			Theirs: map[string]interface{}{"a": "b"},
+			Want:   map[string]interface{}{"a": map[string]interface{}{"b": 3}},
+
+			Conflicts: []string{".a"},
+		},
+		{
+			Base:   []interface{}{1, 2, 3},
+			Ours:   []interface{}{4, 2, 3},
+			Theirs: []interface{}{1, 2, 5, 6},
+			Want:   []interface{}{4, 2, 5, 6},
+		},
+		{
+			Base:   []interface{}{1, 2, 3},
+			Ours:   []interface{}{1, 2},
+			Theirs: []interface{}{1, 4, 3},
+			Want:   []interface{}{1, 4},
+		},
+		{
+			Base:      []interface{}{1, 2, 3},
+			Ours:      []interface{}{1, 2, 3, 4},
+			Theirs:    []interface{}{1, 5, 3, 6},
+			Want:      []interface{}{1, 5, 3, 4},
+			Conflicts: []string{"[3]"},
+		},
+		{
+			Base:   []int{1, 2, 3},
+			Ours:   []int{1, 4, 3},
+			Theirs: []int{1, 2, 5},
+			Want:   []int{1, 4, 5},
+		},
+		{
+			Base:   structA{Foo: 1, Bar: subStruct{A: 1}},
+			Ours:   structA{Foo: 2, Bar: subStruct{A: 1}},
+			Theirs: structA{Foo: 1, Bar: subStruct{A: 3}},
+			Want:   structA{Foo: 2, Bar: subStruct{A: 3}},
+		},
+	} {
+		got, conflicts, err := Merge(test.Base, test.Ours, test.Theirs)
+		if err != nil {
+			t.Errorf("Merge(%+v, %+v, %+v): unexpected error: %s", test.Base, test.Ours, test.Theirs, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("Merge(%+v, %+v, %+v) = %+v, expected %+v", test.Base, test.Ours, test.Theirs, got, test.Want)
+		}
+		if !reflect.DeepEqual(conflicts, test.Conflicts) {
+			t.Errorf(
+				"Merge(%+v, %+v, %+v): conflicts = %q, expected %q",
+				test.Base, test.Ours, test.Theirs, conflicts, test.Conflicts,
+			)
+		}
+	}
+}
+
+func TestMergeError(t *testing.T) {
+	for _, test := range []struct {
+		Base   interface{}
+		Ours   interface{}
+		Theirs interface{}
+	}{
+		{Base: func() {}, Ours: func() {}, Theirs: 1},
+		{Base: []func(){func() {}}, Ours: []func(){}, Theirs: []func(){func() {}}},
+	} {
+		_, _, err := Merge(test.Base, test.Ours, test.Theirs)
+		if err == nil {
+			t.Errorf("Merge(%+v, %+v, %+v): expected error, got nil", test.Base, test.Ours, test.Theirs)
+		}
+	}
+}
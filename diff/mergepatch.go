@@ -0,0 +1,73 @@
+package diff
+
+import (
+	"fmt"
+)
+
+// MergePatch generates an RFC 7396 JSON Merge Patch from a diff tree.
+// Keys missing from the RHS are set to nil and slices are replaced as a whole.
+// When the root of the tree is not a map (or a struct), the RHS is returned.
+//
+// Note that merge patches cannot represent setting a key to null, since null
+// is used to remove keys.
+func MergePatch(d Differ) (interface{}, error) {
+	switch d := d.(type) {
+	case mapDiff:
+		if d.Diff() != TypesDiffer {
+			return mapMergePatch(d)
+		}
+	case structDiff:
+		if d.Diff() != TypesDiffer {
+			return structMergePatch(d)
+		}
+	}
+
+	return RHS(d)
+}
+
+func mapMergePatch(m mapDiff) (interface{}, error) {
+	patch := make(map[string]interface{})
+
+	for key, d := range m.diffs {
+		err := keyMergePatch(patch, fmt.Sprintf("%v", key), d)
+		if err != nil {
+			return patch, err
+		}
+	}
+
+	return patch, nil
+}
+
+func structMergePatch(s structDiff) (interface{}, error) {
+	patch := make(map[string]interface{})
+
+	for key, d := range s.diffs {
+		err := keyMergePatch(patch, key, d)
+		if err != nil {
+			return patch, err
+		}
+	}
+
+	return patch, nil
+}
+
+func keyMergePatch(patch map[string]interface{}, key string, d Differ) error {
+	if d.Diff() == Identical {
+		return nil
+	}
+
+	switch d := d.(type) {
+	case mapMissing:
+		patch[key] = nil
+	case mapExcess:
+		patch[key] = d.value
+	default:
+		v, err := MergePatch(d)
+		if err != nil {
+			return err
+		}
+		patch[key] = v
+	}
+
+	return nil
+}
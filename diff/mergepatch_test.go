@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	for _, test := range []struct {
+		LHS  interface{}
+		RHS  interface{}
+		Want interface{}
+	}{
+		{LHS: 42, RHS: 42, Want: 42},
+		{LHS: 42, RHS: "42", Want: "42"},
+		{
+			LHS:  map[string]interface{}{"a": 1},
+			RHS:  map[string]interface{}{"a": 1},
+			Want: map[string]interface{}{},
+		},
+		{
+			LHS:  map[string]interface{}{"a": 1},
+			RHS:  []interface{}{1},
+			Want: []interface{}{1},
+		},
+		{
+			LHS: map[string]interface{}{
+				"a": 1,
+				"b": 2,
+				"c": []interface{}{1, 2},
+				"d": map[string]interface{}{"e": 3, "f": 4},
+			},
+			RHS: map[string]interface{}{
+				"a": 1,
+				"c": []interface{}{1, 3},
+				"d": map[string]interface{}{"e": 3, "f": 5},
+				"g": "h",
+			},
+			Want: map[string]interface{}{
+				"b": nil,
+				"c": []interface{}{1, 3},
+				"d": map[string]interface{}{"f": 5},
+				"g": "h",
+			},
+		},
+		{
+			LHS:  map[int]string{1: "a", 2: "b"},
+			RHS:  map[int]string{1: "c", 2: "b"},
+			Want: map[string]interface{}{"1": "c"},
+		},
+		{
+			LHS:  structA{Foo: 1, Bar: subStruct{A: 2}},
+			RHS:  structA{Foo: 1, Bar: subStruct{A: 3}},
+			Want: map[string]interface{}{"Bar": map[string]interface{}{"A": 3}},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		got, err := MergePatch(d)
+		if err != nil {
+			t.Errorf("MergePatch(Diff(%+v, %+v)): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("MergePatch(Diff(%+v, %+v)) = %+v, expected %+v", test.LHS, test.RHS, got, test.Want)
+		}
+	}
+}
+
+func TestMergePatchUnsupported(t *testing.T) {
+	_, err := MergePatch(unsupportedDiffer{})
+	if err == nil {
+		t.Errorf("MergePatch(unsupportedDiffer{}): expected error, got nil")
+	}
+}
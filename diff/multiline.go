@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	myersdiff "github.com/mb0/diff"
+)
+
+// multilineContext is the number of unchanged lines shown around the changes of a multiline
+// string diff.
+const multilineContext = 3
+
+// multilineString renders the differences between the lines of lhs and rhs as unified diff
+// hunks, each line being prefixed with prefix.
+func (o Output) multilineString(prefix, lhs, rhs string) string {
+	lhsLines := strings.Split(lhs, "\n")
+	rhsLines := strings.Split(rhs, "\n")
+	changes := myersdiff.Diff(len(lhsLines), len(rhsLines), lineData{lhs: lhsLines, rhs: rhsLines})
+
+	var lines []string
+	for i := 0; i < len(changes); {
+		// changes separated by less than twice the context lines share the same hunk
+		j := i + 1
+		for j < len(changes) && changes[j].A-(changes[j-1].A+changes[j-1].Del) <= 2*multilineContext {
+			j++
+		}
+		first, last := changes[i], changes[j-1]
+		lhsStart := maxInt(first.A-multilineContext, 0)
+		lhsEnd := minInt(last.A+last.Del+multilineContext, len(lhsLines))
+		rhsStart := first.B - (first.A - lhsStart)
+		rhsEnd := last.B + last.Ins + (lhsEnd - last.A - last.Del)
+
+		lines = append(lines, " "+prefix+fmt.Sprintf(
+			"@@ -%s +%s @@",
+			hunkRange(lhsStart, lhsEnd-lhsStart),
+			hunkRange(rhsStart, rhsEnd-rhsStart),
+		))
+		lhsIdx := lhsStart
+		for _, c := range changes[i:j] {
+			for ; lhsIdx < c.A; lhsIdx++ {
+				lines = append(lines, " "+prefix+lhsLines[lhsIdx])
+			}
+			for _, l := range lhsLines[c.A : c.A+c.Del] {
+				lines = append(lines, "-"+prefix+o.colorString(l, color.RedString))
+			}
+			for _, l := range rhsLines[c.B : c.B+c.Ins] {
+				lines = append(lines, "+"+prefix+o.colorString(l, color.GreenString))
+			}
+			lhsIdx = c.A + c.Del
+		}
+		for ; lhsIdx < lhsEnd; lhsIdx++ {
+			lines = append(lines, " "+prefix+lhsLines[lhsIdx])
+		}
+		i = j
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type lineData struct {
+	lhs, rhs []string
+}
+
+func (d lineData) Equal(i, j int) bool {
+	return d.lhs[i] == d.rhs[j]
+}
+
+// hunkRange formats the range of a hunk header, start being 0-based.
+func hunkRange(start, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, n)
+}
+
+func (o Output) colorString(s string, fn colorFn) string {
+	if !o.Colorized {
+		return s
+	}
+
+	return fn("%s", s)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
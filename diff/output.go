@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 
@@ -14,6 +15,13 @@ type Output struct {
 	Colorized  bool
 	JSON       bool
 	JSONValues bool
+	// InlineStrings renders the changes between two strings on a single line, highlighting the
+	// changed segments.
+	InlineStrings bool
+	// MultilineStrings renders the changes between two strings containing newlines as unified
+	// diff hunks. It takes precedence over InlineStrings for these strings, and is ignored for
+	// JSON output (hunks not being valid JSON).
+	MultilineStrings bool
 }
 
 type colorFn func(format string, a ...interface{}) string
@@ -28,6 +36,10 @@ func (o Output) green(v interface{}) string {
 	return o.applyColor(v, color.GreenString)
 }
 
+func (o Output) yellow(v interface{}) string {
+	return o.applyColor(v, color.YellowString)
+}
+
 func (o Output) white(v interface{}) string {
 	return o.applyColor(v, whiteFn)
 }
@@ -37,9 +49,9 @@ func (o Output) applyColor(v interface{}, fn colorFn) string {
 
 	switch {
 	default:
-		s = fmt.Sprintf("%v", v)
+		s = valueString(v)
 	case o.ShowTypes:
-		s = fmt.Sprintf("%T %v", v, v)
+		s = fmt.Sprintf("%T %s", v, valueString(v))
 	case o.JSONValues:
 		s = jsonString(v)
 	}
@@ -59,6 +71,37 @@ func (o Output) typ(v interface{}) string {
 	return ""
 }
 
+// valueString formats v for display. Byte slices are rendered as hexadecimal (see hexBytes).
+func valueString(v interface{}) string {
+	return fmt.Sprintf("%v", hexBytes(v))
+}
+
+// hexBytes replaces the byte slices found in v (including in nested maps and slices, as
+// decoded from MessagePack or CBOR) with their hexadecimal representation, so that bytes are
+// displayed the same way with and without JSON output.
+func hexBytes(v interface{}) interface{} {
+	if b, ok := bytesOf(v); ok {
+		return "0x" + hex.EncodeToString(b)
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, el := range v {
+			m[key] = hexBytes(el)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, el := range v {
+			s[i] = hexBytes(el)
+		}
+		return s
+	}
+
+	return v
+}
+
 func newLineSeparatorString(conf Output) string {
 	if conf.JSON {
 		return ",\n"
@@ -68,7 +111,7 @@ func newLineSeparatorString(conf Output) string {
 }
 
 func jsonString(v interface{}) string {
-	b, err := json.Marshal(v)
+	b, err := json.Marshal(hexBytes(v))
 	if err != nil {
 		panic(fmt.Errorf("unexpected error marshaling value: %s", err))
 	}
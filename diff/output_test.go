@@ -75,6 +75,47 @@ func TestOutput(t *testing.T) {
 	}
 }
 
+func TestOutputBytes(t *testing.T) {
+	for _, test := range []struct {
+		Output Output
+		Want   []string
+	}{
+		{
+			Output: Output{},
+			Want:   []string{"0xcafe"},
+		},
+		{
+			Output: Output{ShowTypes: true},
+			Want:   []string{"[]uint8", "0xcafe"},
+		},
+		{
+			Output: Output{JSONValues: true},
+			Want:   []string{`"0xcafe"`},
+		},
+	} {
+		got := test.Output.white([]byte{0xca, 0xfe})
+		testOut(t, "Output.White([]byte{0xca, 0xfe})", got, test.Want)
+	}
+
+	nested := map[string]interface{}{"a": []interface{}{[]byte{0xca, 0xfe}}}
+	for _, test := range []struct {
+		Output Output
+		Want   []string
+	}{
+		{
+			Output: Output{},
+			Want:   []string{"map[a:[0xcafe]]"},
+		},
+		{
+			Output: Output{JSONValues: true},
+			Want:   []string{`{"a":["0xcafe"]}`},
+		},
+	} {
+		got := test.Output.white(nested)
+		testOut(t, "Output.White(nested)", got, test.Want)
+	}
+}
+
 type erroringMarshaler struct{}
 
 func (erroringMarshaler) MarshalJSON() ([]byte, error) {
@@ -99,3 +140,152 @@ func testOut(t *testing.T, expr, result string, wantStrings []string) {
 		}
 	}
 }
+
+func TestOutputInlineStrings(t *testing.T) {
+	for _, test := range []struct {
+		Output Output
+		LHS    interface{}
+		RHS    interface{}
+		Want   string
+	}{
+		{
+			Output: Output{InlineStrings: true},
+			LHS:    "SELECT id FROM users WHERE age > 21",
+			RHS:    "SELECT id, name FROM users WHERE age > 18",
+			Want:   "~.a: SELECT id{+, name+} FROM users WHERE age > [-21-]{+18+}",
+		},
+		{
+			Output: Output{InlineStrings: true},
+			LHS:    "foo",
+			RHS:    "bar",
+			Want:   "~.a: [-foo-]{+bar+}",
+		},
+		{
+			Output: Output{InlineStrings: true},
+			LHS:    "",
+			RHS:    "bar",
+			Want:   "~.a: {+bar+}",
+		},
+		{
+			Output: Output{InlineStrings: true, ShowTypes: true},
+			LHS:    "hello world",
+			RHS:    "hello there",
+			Want:   "~.a: string hello [-world-]{+there+}",
+		},
+		{
+			Output: Output{InlineStrings: true, JSON: true, JSONValues: true},
+			LHS:    `say "hi"`,
+			RHS:    `say "hello"`,
+			Want:   `~.a: "say \"h[-i-]{+ello+}\""`,
+		},
+		{
+			Output: Output{InlineStrings: true},
+			LHS:    "line 1\nline 2",
+			RHS:    "line 1\nline two",
+			Want:   `~.a: "line 1\nline [-2-]{+two+}"`,
+		},
+		{
+			Output: Output{InlineStrings: true},
+			LHS:    "foo",
+			RHS:    "foo",
+			Want:   " .a: foo",
+		},
+		{
+			Output: Output{InlineStrings: true},
+			LHS:    42,
+			RHS:    43,
+			Want:   "-.a: 42\n+.a: 43",
+		},
+		{
+			Output: Output{},
+			LHS:    "foo",
+			RHS:    "bar",
+			Want:   "-.a: foo\n+.a: bar",
+		},
+	} {
+		got := scalar{lhs: test.LHS, rhs: test.RHS}.StringIndent(".a: ", "", test.Output)
+		if got != test.Want {
+			t.Errorf("scalar{%q, %q}.StringIndent(%+v) = %q, expected %q", test.LHS, test.RHS, test.Output, got, test.Want)
+		}
+	}
+}
+
+func TestOutputMultilineStrings(t *testing.T) {
+	for _, test := range []struct {
+		Output Output
+		LHS    interface{}
+		RHS    interface{}
+		Want   string
+	}{
+		{
+			Output: Output{Indent: "\t", MultilineStrings: true},
+			LHS:    "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl",
+			RHS:    "a\nb\nc\nD\ne\nf\ng\nh\ni\nj\nk\nL",
+			Want: strings.Join([]string{
+				"~.a:",
+				" \t@@ -1,7 +1,7 @@",
+				" \ta",
+				" \tb",
+				" \tc",
+				"-\td",
+				"+\tD",
+				" \te",
+				" \tf",
+				" \tg",
+				" \t@@ -9,4 +9,4 @@",
+				" \ti",
+				" \tj",
+				" \tk",
+				"-\tl",
+				"+\tL",
+			}, "\n"),
+		},
+		{
+			Output: Output{Indent: "\t", MultilineStrings: true},
+			LHS:    "a\nb",
+			RHS:    "a\nb\nc",
+			Want: strings.Join([]string{
+				"~.a:",
+				" \t@@ -1,2 +1,3 @@",
+				" \ta",
+				" \tb",
+				"+\tc",
+			}, "\n"),
+		},
+		{
+			Output: Output{Indent: "  ", MultilineStrings: true, ShowTypes: true},
+			LHS:    "a",
+			RHS:    "b\nc",
+			Want: strings.Join([]string{
+				"~.a: string",
+				"   @@ -1 +1,2 @@",
+				"-  a",
+				"+  b",
+				"+  c",
+			}, "\n"),
+		},
+		{
+			Output: Output{Indent: "\t", MultilineStrings: true, InlineStrings: true},
+			LHS:    "foo",
+			RHS:    "bar",
+			Want:   "~.a: [-foo-]{+bar+}",
+		},
+		{
+			Output: Output{Indent: "\t"},
+			LHS:    "a\nb",
+			RHS:    "a\nc",
+			Want:   "-.a: a\nb\n+.a: a\nc",
+		},
+		{
+			Output: Output{Indent: "\t", MultilineStrings: true, JSON: true, JSONValues: true},
+			LHS:    "a\nb",
+			RHS:    "a\nc",
+			Want:   "-.a: \"a\\nb\",\n+.a: \"a\\nc\"",
+		},
+	} {
+		got := scalar{lhs: test.LHS, rhs: test.RHS}.StringIndent(".a: ", "", test.Output)
+		if got != test.Want {
+			t.Errorf("scalar{%q, %q}.StringIndent(%+v) = %q, expected %q", test.LHS, test.RHS, test.Output, got, test.Want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
+// Path is the location of a node in a diff tree, as passed to WalkFnV2.
+type Path []PathElement
+
+// PathElementKind is the kind of a PathElement.
+type PathElementKind int
+
+// Kinds of PathElement.
+const (
+	// KeyElement is a map key or a struct field.
+	KeyElement PathElementKind = iota
+	// IndexElement is the index of a slice or stream element.
+	IndexElement
+	// LabelElement identifies an element of a slice matched by key (see KeyedSlices).
+	LabelElement
+)
+
+// PathElement is a single element of a Path.
+type PathElement struct {
+	Kind PathElementKind
+	// Key is the map key or struct field name of KeyElement elements.
+	Key interface{}
+	// Index is the index of IndexElement and LabelElement elements.
+	Index int
+	// Label is the label of LabelElement elements (i.e `id=42`).
+	Label string
+}
+
+// String returns the element as found in jaydiff's paths (i.e `.foo`, `["a.b"]` or `[3]`).
+func (e PathElement) String() string {
+	switch e.Kind {
+	case IndexElement:
+		return "[" + strconv.Itoa(e.Index) + "]"
+	case LabelElement:
+		return "[" + e.Label + "]"
+	}
+
+	return "." + jpath.EscapeKey(e.Key)
+}
+
+// String returns the path in the format used by jaydiff and passed to WalkFn (i.e `.a["b.c"][3]`).
+func (p Path) String() string {
+	var b strings.Builder
+	for _, e := range p {
+		b.WriteString(e.String())
+	}
+
+	return b.String()
+}
+
+// JSONPointer returns the path as an RFC 6901 JSON Pointer (i.e `/a/b.c/3`).
+// Elements of slices matched by key are referred to using their index.
+func (p Path) JSONPointer() string {
+	var b strings.Builder
+	for _, e := range p {
+		b.WriteString("/")
+		if e.Kind == KeyElement {
+			b.WriteString(pointerEscaper.Replace(fmt.Sprintf("%v", e.Key)))
+			continue
+		}
+		b.WriteString(strconv.Itoa(e.Index))
+	}
+
+	return b.String()
+}
+
+var jsonPathIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var jsonPathEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// JSONPath returns the path as a JSONPath expression (i.e `$.a['b.c'][3]`).
+// Elements of slices matched by key are referred to using their index.
+func (p Path) JSONPath() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, e := range p {
+		if e.Kind != KeyElement {
+			b.WriteString("[" + strconv.Itoa(e.Index) + "]")
+			continue
+		}
+		key := fmt.Sprintf("%v", e.Key)
+		if jsonPathIdentifier.MatchString(key) {
+			b.WriteString("." + key)
+			continue
+		}
+		b.WriteString("['" + jsonPathEscaper.Replace(key) + "']")
+	}
+
+	return b.String()
+}
+
+// append returns a copy of p with elem appended.
+func (p Path) append(elem PathElement) Path {
+	res := make(Path, len(p), len(p)+1)
+	copy(res, p)
+
+	return append(res, elem)
+}
@@ -0,0 +1,48 @@
+package diff
+
+import "testing"
+
+func TestPath(t *testing.T) {
+	for _, test := range []struct {
+		Path        Path
+		String      string
+		JSONPointer string
+		JSONPath    string
+	}{
+		{Path: Path{}, String: "", JSONPointer: "", JSONPath: "$"},
+		{
+			Path:        Path{{Kind: KeyElement, Key: "a"}, {Kind: IndexElement, Index: 3}},
+			String:      ".a[3]",
+			JSONPointer: "/a/3",
+			JSONPath:    "$.a[3]",
+		},
+		{
+			Path:        Path{{Kind: KeyElement, Key: "b.c"}, {Kind: KeyElement, Key: "d/e~f"}},
+			String:      `."b.c".d/e~f`,
+			JSONPointer: "/b.c/d~1e~0f",
+			JSONPath:    `$['b.c']['d/e~f']`,
+		},
+		{
+			Path:        Path{{Kind: KeyElement, Key: `it's`}, {Kind: KeyElement, Key: 42}},
+			String:      `.it's.42`,
+			JSONPointer: "/it's/42",
+			JSONPath:    `$['it\'s']['42']`,
+		},
+		{
+			Path:        Path{{Kind: KeyElement, Key: "users"}, {Kind: LabelElement, Index: 1, Label: "id=2"}},
+			String:      ".users[id=2]",
+			JSONPointer: "/users/1",
+			JSONPath:    "$.users[1]",
+		},
+	} {
+		if got := test.Path.String(); got != test.String {
+			t.Errorf("%#v.String() = %q, expected %q", test.Path, got, test.String)
+		}
+		if got := test.Path.JSONPointer(); got != test.JSONPointer {
+			t.Errorf("%#v.JSONPointer() = %q, expected %q", test.Path, got, test.JSONPointer)
+		}
+		if got := test.Path.JSONPath(); got != test.JSONPath {
+			t.Errorf("%#v.JSONPath() = %q, expected %q", test.Path, got, test.JSONPath)
+		}
+	}
+}
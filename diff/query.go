@@ -0,0 +1,88 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
+// side is the state of a query matched against one side (LHS or RHS) of a diff tree.
+type side struct {
+	m jpath.Matcher
+	// ok is false if the current node does not exist on this side
+	ok bool
+}
+
+// MatchQuery returns the paths of the nodes of d whose LHS or RHS value is matched by q. Each side
+// is matched as if q was executed on its value, the indices of slice elements being their indices
+// in the LHS or RHS (see Path for the way they are reported). The descendants of matched nodes are
+// not returned.
+func MatchQuery(d Differ, q *jpath.Query) []Path {
+	_, lhsErr := LHS(d)
+	_, rhsErr := RHS(d)
+
+	return matchQuery(d, Path{}, side{q.Matcher(), lhsErr == nil}, side{q.Matcher(), rhsErr == nil}, nil)
+}
+
+func matchQuery(d Differ, path Path, lhs, rhs side, res []Path) []Path {
+	if (lhs.ok && lhs.m.Matched()) || (rhs.ok && rhs.m.Matched()) {
+		return append(res, path)
+	}
+
+	switch d := d.(type) {
+	case mapDiff:
+		for _, k := range d.sortedKeys() {
+			res = matchKey(d.diffs[k], path.append(PathElement{Kind: KeyElement, Key: k}), fmt.Sprintf("%v", k), lhs, rhs, res)
+		}
+	case structDiff:
+		for _, k := range d.sortedKeys() {
+			res = matchKey(d.diffs[k], path.append(PathElement{Kind: KeyElement, Key: k}), k, lhs, rhs, res)
+		}
+	case slice:
+		res = matchIndices(d.diffs, path, d.pathElement, d.indices, rhsIndices(d.diffs, d.pairs), lhs, rhs, res)
+	case stream:
+		elem := func(i int) PathElement {
+			return PathElement{Kind: IndexElement, Index: d.lhsIndex(i)}
+		}
+		res = matchIndices(d.diffs, path, elem, d.indices, rhsIndices(d.diffs, nil), lhs, rhs, res)
+	}
+
+	return res
+}
+
+func matchKey(d Differ, path Path, key string, lhs, rhs side, res []Path) []Path {
+	var childLHS, childRHS side
+	if v, err := LHS(d); err == nil && lhs.ok {
+		childLHS = side{lhs.m.Key(key, v), true}
+	}
+	if v, err := RHS(d); err == nil && rhs.ok {
+		childRHS = side{rhs.m.Key(key, v), true}
+	}
+
+	return matchQuery(d, path, childLHS, childRHS, res)
+}
+
+func matchIndices(diffs []Differ, path Path, elem func(int) PathElement, lhsIdx, rhsIdx []int, lhs, rhs side, res []Path) []Path {
+	var lhsLen, rhsLen int
+	for _, d := range diffs {
+		if !IsExcess(d) {
+			lhsLen++
+		}
+		if !IsMissing(d) {
+			rhsLen++
+		}
+	}
+
+	for i, d := range diffs {
+		var childLHS, childRHS side
+		if v, err := LHS(d); err == nil && lhs.ok {
+			childLHS = side{lhs.m.Index(lhsIdx[i], lhsLen, v), true}
+		}
+		if v, err := RHS(d); err == nil && rhs.ok {
+			childRHS = side{rhs.m.Index(rhsIdx[i], rhsLen, v), true}
+		}
+		res = matchQuery(d, path.append(elem(i)), childLHS, childRHS, res)
+	}
+
+	return res
+}
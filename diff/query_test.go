@@ -0,0 +1,104 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yazgazan/jaydiff/jpath"
+)
+
+func TestMatchQuery(t *testing.T) {
+	users := func(names ...string) []interface{} {
+		res := make([]interface{}, len(names))
+		for i, name := range names {
+			res[i] = map[string]interface{}{"id": i, "name": name}
+		}
+		return res
+	}
+
+	for _, test := range []struct {
+		LHS   interface{}
+		RHS   interface{}
+		Opts  []ConfigOpt
+		Query string
+		Paths []string
+	}{
+		{
+			LHS:   map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": 2}},
+			RHS:   map[string]interface{}{"a": 2, "b": map[string]interface{}{"c": 3}},
+			Query: "$..c",
+			Paths: []string{".b.c"},
+		},
+		{
+			LHS:   map[string]interface{}{"a": 1, "b": 2},
+			RHS:   map[string]interface{}{"b": 3, "c": 4},
+			Query: "$.*",
+			Paths: []string{".a", ".b", ".c"},
+		},
+		{
+			LHS:   users("a", "b", "c"),
+			RHS:   users("a", "d"),
+			Query: "$[?(@.name == 'd')]",
+			Paths: []string{"[1]"},
+		},
+		{
+			LHS:   users("a", "b", "c"),
+			RHS:   users("a", "d"),
+			Query: "$[-1]",
+			Paths: []string{"[1]", "[2]"},
+		},
+		{
+			LHS:   users("a", "b"),
+			RHS:   []interface{}{map[string]interface{}{"id": 2, "name": "c"}, map[string]interface{}{"id": 1, "name": "d"}},
+			Opts:  []ConfigOpt{KeyedSlices("id")},
+			Query: "$[?(@.id == 1)].name",
+			Paths: []string{"[id=1].name"},
+		},
+		{
+			LHS:   users("a", "b"),
+			RHS:   []interface{}{map[string]interface{}{"id": 2, "name": "c"}, map[string]interface{}{"id": 1, "name": "d"}},
+			Opts:  []ConfigOpt{KeyedSlices("id")},
+			Query: "$[0]",
+			Paths: []string{"[id=0]", "[id=2]"},
+		},
+		{
+			LHS:   []int{1, 2, 3},
+			RHS:   []int{0, 1, 2, 3, 4},
+			Opts:  []ConfigOpt{UseSliceMyers()},
+			Query: "$[?(@ == 0 || @ == 4)]",
+			Paths: []string{"[0]", "[3]"},
+		},
+		{
+			LHS:   []int{1, 2, 3, 4},
+			RHS:   []int{4, 2, 3, 1},
+			Opts:  []ConfigOpt{UseSliceMyers()},
+			Query: "$[0]",
+			Paths: []string{"[0]", "[3]"},
+		},
+		{
+			LHS:   mockStream(map[string]interface{}{"a": 1}, map[string]interface{}{"b": 2}),
+			RHS:   mockStream(map[string]interface{}{"a": 1}, map[string]interface{}{"b": 3}, 4),
+			Query: "$..b",
+			Paths: []string{"[1].b"},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		q, err := jpath.CompileQuery(test.Query)
+		if err != nil {
+			t.Errorf("CompileQuery(%q): unexpected error: %s", test.Query, err)
+			continue
+		}
+
+		var got []string
+		for _, path := range MatchQuery(d, q) {
+			got = append(got, path.String())
+		}
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf("MatchQuery(Diff(%+v, %+v), %q) = %q, expected %q", test.LHS, test.RHS, test.Query, got, test.Paths)
+		}
+	}
+}
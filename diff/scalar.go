@@ -1,13 +1,30 @@
 package diff
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strings"
 )
 
 type scalar struct {
 	lhs interface{}
 	rhs interface{}
+
+	// tolerance is used to compare floats (see FloatTolerance)
+	tolerance floatTolerance
+}
+
+func newScalar(c config, lhs, rhs reflect.Value) scalar {
+	s := scalar{lhs: lhs.Interface(), rhs: rhs.Interface()}
+	if len(c.tolerances) != 0 && isNumber(lhs) && isNumber(rhs) {
+		s.tolerance = c.toleranceAt()
+	}
+
+	return s
 }
 
 func (s scalar) Diff() Type {
@@ -17,23 +34,86 @@ func (s scalar) Diff() Type {
 	if lhsVal.Kind() != rhsVal.Kind() {
 		return TypesDiffer
 	}
-	if s.lhs != s.rhs {
+	if s.tolerance.abs != 0 || s.tolerance.rel != 0 {
+		lhsFloat, lhsOk := floatValue(lhsVal)
+		rhsFloat, rhsOk := floatValue(rhsVal)
+		if lhsOk && rhsOk && s.tolerance.equal(lhsFloat, rhsFloat) {
+			return Identical
+		}
+	}
+	if !scalarEqual(s.lhs, s.rhs) {
 		return ContentDiffer
 	}
 
 	return Identical
 }
 
+func (t floatTolerance) equal(lhs, rhs float64) bool {
+	delta := math.Abs(lhs - rhs)
+
+	return delta <= t.abs || delta <= t.rel*math.Max(math.Abs(lhs), math.Abs(rhs))
+}
+
+func isNumber(v reflect.Value) bool {
+	_, ok := floatValue(v)
+
+	return ok
+}
+
+// floatValue returns the value of floats and json.Numbers as a float64.
+func floatValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	case reflect.String:
+		n, ok := v.Interface().(json.Number)
+		if !ok {
+			return 0, false
+		}
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+func scalarEqual(lhs, rhs interface{}) bool {
+	lhsBytes, lhsOk := bytesOf(lhs)
+	rhsBytes, rhsOk := bytesOf(rhs)
+	if lhsOk && rhsOk {
+		return reflect.TypeOf(lhs) == reflect.TypeOf(rhs) && bytes.Equal(lhsBytes, rhsBytes)
+	}
+	lhsNumber, lhsOk := lhs.(json.Number)
+	rhsNumber, rhsOk := rhs.(json.Number)
+	if lhsOk && rhsOk {
+		return numberEqual(lhsNumber, rhsNumber)
+	}
+
+	return lhs == rhs
+}
+
+// numberEqual compares two json.Numbers without loss of precision, so that `1.0` equals `1`.
+// Numbers that cannot be parsed are compared as strings.
+func numberEqual(lhs, rhs json.Number) bool {
+	lhsRat, lhsOk := new(big.Rat).SetString(string(lhs))
+	rhsRat, rhsOk := new(big.Rat).SetString(string(rhs))
+	if !lhsOk || !rhsOk {
+		return lhs == rhs
+	}
+
+	return lhsRat.Cmp(rhsRat) == 0
+}
+
 func (s scalar) Strings() []string {
 	if s.Diff() == Identical {
 		return []string{
-			fmt.Sprintf("  %T %v", s.lhs, s.lhs),
+			fmt.Sprintf("  %T %s", s.lhs, valueString(s.lhs)),
 		}
 	}
 
 	return []string{
-		fmt.Sprintf("- %T %v", s.lhs, s.lhs),
-		fmt.Sprintf("+ %T %v", s.rhs, s.rhs),
+		fmt.Sprintf("- %T %s", s.lhs, valueString(s.lhs)),
+		fmt.Sprintf("+ %T %s", s.rhs, valueString(s.rhs)),
 	}
 }
 
@@ -41,6 +121,15 @@ func (s scalar) StringIndent(key, prefix string, conf Output) string {
 	if s.Diff() == Identical {
 		return " " + prefix + key + conf.white(s.lhs)
 	}
+	lhs, lhsOk := s.lhs.(string)
+	rhs, rhsOk := s.rhs.(string)
+	if conf.MultilineStrings && !conf.JSON && lhsOk && rhsOk && (strings.Contains(lhs, "\n") || strings.Contains(rhs, "\n")) {
+		return "~" + strings.TrimRight(prefix+key+conf.typ(s.lhs), " ") + "\n" +
+			conf.multilineString(prefix+conf.Indent, lhs, rhs)
+	}
+	if conf.InlineStrings && lhsOk && rhsOk {
+		return "~" + prefix + key + conf.inlineString(lhs, rhs)
+	}
 
 	return "-" + prefix + key + conf.red(s.lhs) + newLineSeparatorString(conf) +
 		"+" + prefix + key + conf.green(s.rhs)
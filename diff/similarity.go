@@ -0,0 +1,118 @@
+package diff
+
+import (
+	"reflect"
+	"strconv"
+
+	myersdiff "github.com/mb0/diff"
+)
+
+// similarityThreshold is the minimum similarity (see similarity) for a deleted element to be
+// paired with an inserted one.
+const similarityThreshold = 0.5
+
+// pairSimilar appends the elements deleted and inserted by a Myers change to res. Deleted and
+// inserted containers whose diff is mostly identical are paired, preserving the order of both
+// sides and maximizing the total similarity. The other elements are reported as missing or
+// excess, the RHS index of excess elements being stored in rhsIndices.
+func pairSimilar(conf config, lhs, rhs reflect.Value, c myersdiff.Change, res []Differ, rhsIndices map[int]int) []Differ {
+	pairs := make([][]Differ, c.Del)
+	scores := make([][]float64, c.Del)
+	for d := range pairs {
+		pairs[d] = make([]Differ, c.Ins)
+		scores[d] = make([]float64, c.Ins)
+		if !isContainer(lhs.Index(c.A + d)) {
+			continue
+		}
+		for i := range pairs[d] {
+			if !isContainer(rhs.Index(c.B + i)) {
+				continue
+			}
+			diff, _ := diff(conf.at("["+strconv.Itoa(c.A+d)+"]"), lhs.Index(c.A+d).Interface(), rhs.Index(c.B+i).Interface(), &visited{})
+			if score := similarity(diff); score >= similarityThreshold {
+				pairs[d][i] = diff
+				scores[d][i] = score
+			}
+		}
+	}
+
+	// best[d][i] is the highest total similarity when pairing the deleted elements from d
+	// with the inserted elements from i.
+	best := make([][]float64, c.Del+1)
+	for d := range best {
+		best[d] = make([]float64, c.Ins+1)
+	}
+	for d := c.Del - 1; d >= 0; d-- {
+		for i := c.Ins - 1; i >= 0; i-- {
+			best[d][i] = maxFloat(best[d+1][i], best[d][i+1])
+			if pairs[d][i] != nil {
+				best[d][i] = maxFloat(best[d][i], scores[d][i]+best[d+1][i+1])
+			}
+		}
+	}
+
+	d, i := 0, 0
+	for d < c.Del || i < c.Ins {
+		switch {
+		case d < c.Del && i < c.Ins && pairs[d][i] != nil && best[d][i] == scores[d][i]+best[d+1][i+1]:
+			res = append(res, pairs[d][i])
+			d++
+			i++
+		case d < c.Del && (i == c.Ins || best[d][i] == best[d+1][i]):
+			res = append(res, sliceMissing{lhs.Index(c.A + d).Interface()})
+			d++
+		default:
+			rhsIndices[len(res)] = c.B + i
+			res = append(res, sliceExcess{rhs.Index(c.B + i).Interface()})
+			i++
+		}
+	}
+
+	return res
+}
+
+// similarity returns the ratio of identical leaves in the diff between two containers, or 0 if
+// the values are not containers of the same type.
+func similarity(d Differ) float64 {
+	if _, ok := d.(Walker); !ok || d.Diff() != ContentDiffer {
+		return 0
+	}
+
+	var leaves, identical int
+	_, _ = Walk(d, func(_ Differ, d Differ, _ string) (Differ, error) {
+		if _, ok := d.(Walker); ok && d.Diff() != TypesDiffer {
+			return nil, nil
+		}
+		leaves++
+		if d.Diff() == Identical {
+			identical++
+		}
+
+		return nil, nil
+	})
+	if leaves == 0 {
+		return 0
+	}
+
+	return float64(identical) / float64(leaves)
+}
+
+func isContainer(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return !valueIsBytes(v)
+	}
+
+	return false
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -3,6 +3,7 @@ package diff
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,8 +13,13 @@ import (
 type slice struct {
 	diffs   []Differ
 	indices []int
-	lhs     interface{}
-	rhs     interface{}
+	// labels identify the elements by key instead of index (see KeyedSlices)
+	labels []string
+	// pairs holds the RHS index of the elements matched regardless of their position (see
+	// KeyedSlices and UnorderedSlices), -1 for missing elements
+	pairs []int
+	lhs   interface{}
+	rhs   interface{}
 }
 
 type sliceMissing struct {
@@ -24,6 +30,14 @@ type sliceExcess struct {
 	value interface{}
 }
 
+// sliceMoved is an element found at different positions in the LHS and RHS (see UseSliceMyers).
+// from is its index in the LHS, to its index in the RHS.
+type sliceMoved struct {
+	value interface{}
+	from  int
+	to    int
+}
+
 type diffData struct {
 	lhs       reflect.Value
 	rhs       reflect.Value
@@ -33,7 +47,7 @@ type diffData struct {
 }
 
 func (d *diffData) Equal(i, j int) bool {
-	diff, err := diff(d.c, d.lhs.Index(i).Interface(), d.rhs.Index(j).Interface(), d.visited)
+	diff, err := diff(d.c.at("["+strconv.Itoa(i)+"]"), d.lhs.Index(i).Interface(), d.rhs.Index(j).Interface(), d.visited)
 	if err != nil {
 		d.lastError = err
 		return false
@@ -44,38 +58,133 @@ func (d *diffData) Equal(i, j int) bool {
 
 func myersToDiff(conf config, lhs, rhs reflect.Value, changes []myersdiff.Change) ([]Differ, []int) {
 	res := []Differ{}
-	indices := []int{}
+	// rhsIndices holds the RHS index of the excess elements, by position in res.
+	rhsIndices := map[int]int{}
 
 	lhsIdx := 0
 	rhsIdx := 0
 	for _, c := range changes {
 		for i := 0; lhsIdx+i < c.A; i++ {
-			diff, _ := diff(conf, lhs.Index(lhsIdx+i).Interface(), rhs.Index(rhsIdx+i).Interface(), &visited{})
+			diff, _ := diff(conf.at("["+strconv.Itoa(lhsIdx+i)+"]"), lhs.Index(lhsIdx+i).Interface(), rhs.Index(rhsIdx+i).Interface(), &visited{})
 			res = append(res, diff)
-			indices = append(indices, lhsIdx+i)
-		}
-		lhsIdx = c.A
-		rhsIdx = c.B
-		for d := 0; d < c.Del; d++ {
-			res = append(res, sliceMissing{lhs.Index(lhsIdx + d).Interface()})
-			indices = append(indices, lhsIdx+d)
 		}
-		for i := 0; i < c.Ins; i++ {
-			res = append(res, sliceExcess{rhs.Index(rhsIdx + i).Interface()})
-			indices = append(indices, lhsIdx+i)
-		}
-		lhsIdx += c.Del
-		rhsIdx += c.Ins
+		res = pairSimilar(conf, lhs, rhs, c, res, rhsIndices)
+		lhsIdx = c.A + c.Del
+		rhsIdx = c.B + c.Ins
 	}
 
 	for lhsIdx < lhs.Len() && rhsIdx < rhs.Len() {
-		diff, _ := diff(conf, lhs.Index(lhsIdx).Interface(), rhs.Index(rhsIdx).Interface(), &visited{})
+		diff, _ := diff(conf.at("["+strconv.Itoa(lhsIdx)+"]"), lhs.Index(lhsIdx).Interface(), rhs.Index(rhsIdx).Interface(), &visited{})
 		res = append(res, diff)
-		indices = append(indices, lhsIdx)
 		lhsIdx++
 		rhsIdx++
 	}
-	return res, indices
+
+	return detectMoves(conf, res, sliceIndices(res), rhsIndices)
+}
+
+// detectMoves replaces the missing elements identical to an excess element with a sliceMoved
+// node, removing the excess element. Indices are recomputed when elements are moved.
+func detectMoves(conf config, diffs []Differ, indices []int, rhsIndices map[int]int) ([]Differ, []int) {
+	moved := map[int]bool{}
+	for i, d := range diffs {
+		missing, ok := d.(sliceMissing)
+		if !ok {
+			continue
+		}
+		for j, d := range diffs {
+			excess, ok := d.(sliceExcess)
+			if !ok || moved[j] {
+				continue
+			}
+			diff, _ := diff(conf.at("["+strconv.Itoa(indices[i])+"]"), missing.value, excess.value, &visited{})
+			if diff.Diff() != Identical {
+				continue
+			}
+			diffs[i] = sliceMoved{value: missing.value, from: indices[i], to: rhsIndices[j]}
+			moved[j] = true
+			break
+		}
+	}
+	if len(moved) == 0 {
+		return diffs, indices
+	}
+
+	res := make([]Differ, 0, len(diffs)-len(moved))
+	for i, d := range diffs {
+		if !moved[i] {
+			res = append(res, d)
+		}
+	}
+
+	return res, sliceIndices(res)
+}
+
+// rhsIndices computes the indices of the elements of a slice (or stream) diff relative to the RHS,
+// -1 for missing elements. pairs is used as-is for slices compared regardless of the position of
+// their elements. Otherwise, the elements are placed as done by Apply.
+func rhsIndices(diffs []Differ, pairs []int) []int {
+	if pairs != nil {
+		return pairs
+	}
+
+	res := make([]int, len(diffs))
+	// order holds the position in diffs of the elements, in the order of the RHS
+	var order, moved []int
+	for i, d := range diffs {
+		res[i] = -1
+		switch d.(type) {
+		case sliceMissing, streamMissing:
+		case sliceMoved:
+			moved = append(moved, i)
+		default:
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(moved, func(i, j int) bool {
+		return diffs[moved[i]].(sliceMoved).to < diffs[moved[j]].(sliceMoved).to
+	})
+	for _, i := range moved {
+		to := minInt(diffs[i].(sliceMoved).to, len(order))
+		order = append(order, 0)
+		copy(order[to+1:], order[to:])
+		order[to] = i
+	}
+	for j, i := range order {
+		res[i] = j
+	}
+
+	return res
+}
+
+// sliceIndices computes the indices of the elements of a slice diff relative to the LHS.
+// Within a run of missing and excess elements, excess elements are indexed from the start
+// of the run.
+func sliceIndices(diffs []Differ) []int {
+	indices := make([]int, len(diffs))
+
+	lhsIdx, runStart, nExcess := 0, 0, 0
+	inRun := false
+	for i, d := range diffs {
+		switch d.(type) {
+		case sliceMissing, sliceExcess:
+			if !inRun {
+				runStart, nExcess, inRun = lhsIdx, 0, true
+			}
+		default:
+			inRun = false
+		}
+
+		if isExcessElement(d) {
+			indices[i] = runStart + nExcess
+			nExcess++
+			continue
+		}
+		indices[i] = lhsIdx
+		lhsIdx++
+	}
+
+	return indices
 }
 
 func newMyersSlice(c config, lhs, rhs interface{}, visited *visited) (Differ, error) {
@@ -155,7 +264,7 @@ func sliceNewSameTypes(c config, lhsVal, rhsVal reflect.Value, visited *visited)
 	for i := 0; i < nElems; i++ {
 		indices = append(indices, i)
 		if i < lhsVal.Len() && i < rhsVal.Len() {
-			diff, err := diff(c, lhsVal.Index(i).Interface(), rhsVal.Index(i).Interface(), visited)
+			diff, err := diff(c.at("["+strconv.Itoa(i)+"]"), lhsVal.Index(i).Interface(), rhsVal.Index(i).Interface(), visited)
 			diffs = append(diffs, diff)
 
 			if err != nil {
@@ -265,13 +374,17 @@ func (s slice) openString(key, prefix string, conf Output) string {
 }
 
 func (s slice) Walk(path string, fn WalkFn) error {
+	return s.walkElements(walkString(s, path, fn))
+}
+
+func (s slice) walkElements(fn func(PathElement, Differ) (Differ, error)) error {
 	for i, diff := range s.diffs {
-		d, err := walk(s, diff, path+"["+strconv.Itoa(s.lhsIndex(i))+"]", fn)
+		d, err := fn(s.pathElement(i), diff)
 		if err != nil {
 			return err
 		}
 		if d != nil {
-			s.diffs[i] = d
+			s.diffs[i] = ignoring(diff, d)
 		}
 	}
 
@@ -282,6 +395,14 @@ func (s slice) lhsIndex(i int) int {
 	return s.indices[i]
 }
 
+func (s slice) pathElement(i int) PathElement {
+	if s.labels != nil {
+		return PathElement{Kind: LabelElement, Index: s.lhsIndex(i), Label: s.labels[i]}
+	}
+
+	return PathElement{Kind: IndexElement, Index: s.lhsIndex(i)}
+}
+
 func (s slice) LHS() interface{} {
 	return s.lhs
 }
@@ -325,3 +446,25 @@ func (e sliceExcess) StringIndent(key, prefix string, conf Output) string {
 func (e sliceExcess) RHS() interface{} {
 	return e.value
 }
+
+func (m sliceMoved) Diff() Type {
+	return ContentDiffer
+}
+
+func (m sliceMoved) Strings() []string {
+	return []string{
+		fmt.Sprintf("~ %T %v (moved from [%d] to [%d])", m.value, m.value, m.from, m.to),
+	}
+}
+
+func (m sliceMoved) StringIndent(key, prefix string, conf Output) string {
+	return "~" + prefix + key + conf.yellow(m.value) + fmt.Sprintf(" (moved from [%d] to [%d])", m.from, m.to)
+}
+
+func (m sliceMoved) LHS() interface{} {
+	return m.value
+}
+
+func (m sliceMoved) RHS() interface{} {
+	return m.value
+}
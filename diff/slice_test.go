@@ -1,6 +1,7 @@
 package diff
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -190,6 +191,18 @@ func TestSliceMyers(t *testing.T) {
 			},
 			Type: ContentDiffer,
 		},
+		{
+			LHS: []int{1, 2, 3},
+			RHS: []int{2, 3, 1},
+			Want: [][]string{
+				{},
+				{"~", "int", "1", "moved from [0] to [2]"},
+				{"int", "2"},
+				{"int", "3"},
+				{},
+			},
+			Type: ContentDiffer,
+		},
 	} {
 		typ, err := c.sliceFn(c, test.LHS, test.RHS, &visited{})
 
@@ -242,3 +255,60 @@ func TestSliceMyers(t *testing.T) {
 		t.Errorf("invalidSlice.StringIndent(%q, %q, %+v) = %q, expected %q", testKey, testPrefix, testOutput, indented, "")
 	}
 }
+
+func TestSliceMyersSimilar(t *testing.T) {
+	for _, test := range []struct {
+		LHS   []interface{}
+		RHS   []interface{}
+		Paths []string
+	}{
+		{
+			LHS: []interface{}{
+				map[string]interface{}{"a": 1, "b": 2, "c": 3},
+				map[string]interface{}{"a": 4, "b": 5, "c": 6},
+			},
+			RHS: []interface{}{
+				map[string]interface{}{"a": 1, "b": 2, "c": 7},
+				map[string]interface{}{"a": 4, "b": 8, "c": 6},
+			},
+			Paths: []string{"[0].c", "[1].b"},
+		},
+		{
+			LHS: []interface{}{
+				map[string]interface{}{"a": 1, "b": 2, "c": 3},
+				map[string]interface{}{"a": 4, "b": 5, "c": 6},
+			},
+			RHS: []interface{}{
+				map[string]interface{}{"a": 7, "b": 8, "c": 9},
+				map[string]interface{}{"a": 4, "b": 5, "c": 0},
+			},
+			Paths: []string{"[0]", "[0]", "[1].c"},
+		},
+		{
+			LHS: []interface{}{
+				[]interface{}{1, 2, 3},
+				"foo",
+			},
+			RHS: []interface{}{
+				"bar",
+				[]interface{}{1, 2, 4},
+			},
+			Paths: []string{"[0]", "[0][2]", "[0][2]", "[1]"},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, UseSliceMyers())
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		paths, err := changedPaths(d)
+		if err != nil {
+			t.Errorf("Walk(Diff(%+v, %+v)): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		if !reflect.DeepEqual(paths, test.Paths) {
+			t.Errorf("Diff(%+v, %+v): changed paths = %v, expected %v", test.LHS, test.RHS, paths, test.Paths)
+		}
+	}
+}
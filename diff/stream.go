@@ -69,7 +69,7 @@ func newStream(c config, lhs, rhs interface{}, visited *visited) (Differ, error)
 	for i := 0; ; i++ {
 		indices = append(indices, i)
 
-		d, lhsVal, rhsVal, err := diffStreamValues(c, lhsStream, rhsStream, visited)
+		d, lhsVal, rhsVal, err := diffStreamValues(c.at("["+strconv.Itoa(i)+"]"), lhsStream, rhsStream, visited)
 		if err == io.EOF {
 			break
 		}
@@ -178,13 +178,17 @@ func (s stream) openString(key, prefix string, conf Output) string {
 }
 
 func (s stream) Walk(path string, fn WalkFn) error {
+	return s.walkElements(walkString(s, path, fn))
+}
+
+func (s stream) walkElements(fn func(PathElement, Differ) (Differ, error)) error {
 	for i, diff := range s.diffs {
-		d, err := walk(s, diff, path+"["+strconv.Itoa(s.lhsIndex(i))+"]", fn)
+		d, err := fn(PathElement{Kind: IndexElement, Index: s.lhsIndex(i)}, diff)
 		if err != nil {
 			return err
 		}
 		if d != nil {
-			s.diffs[i] = d
+			s.diffs[i] = ignoring(diff, d)
 		}
 	}
 
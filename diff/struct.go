@@ -37,7 +37,7 @@ func newStruct(c config, lhs, rhs interface{}, visited *visited) (Differ, error)
 				continue
 			}
 
-			diff, err := diff(c, lhsFVal.Interface(), rhsFVal.Interface(), visited)
+			diff, err := diff(c.at("."+jpath.EscapeKey(fType.Name)), lhsFVal.Interface(), rhsFVal.Interface(), visited)
 			diffs[fType.Name] = diff
 
 			if err != nil {
@@ -170,17 +170,12 @@ func (s structDiff) closeString(prefix string, conf Output) string {
 }
 
 func (s structDiff) Walk(path string, fn WalkFn) error {
-	keys := make([]string, 0, len(s.diffs))
-
-	for k := range s.diffs {
-		keys = append(keys, k)
-	}
-
-	sort.Strings(keys)
+	return s.walkElements(walkString(s, path, fn))
+}
 
-	for _, k := range keys {
-		diff := s.diffs[k]
-		d, err := walk(s, diff, path+"."+jpath.EscapeKey(k), fn)
+func (s structDiff) walkElements(fn func(PathElement, Differ) (Differ, error)) error {
+	for _, k := range s.sortedKeys() {
+		d, err := fn(PathElement{Kind: KeyElement, Key: k}, s.diffs[k])
 		if err != nil {
 			return err
 		}
@@ -192,6 +187,18 @@ func (s structDiff) Walk(path string, fn WalkFn) error {
 	return nil
 }
 
+func (s structDiff) sortedKeys() []string {
+	keys := make([]string, 0, len(s.diffs))
+
+	for k := range s.diffs {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 func (s structDiff) LHS() interface{} {
 	return s.lhs
 }
@@ -0,0 +1,68 @@
+package diff
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// newUnorderedSlice compares two slices as multisets (see UnorderedSlices). Each element of
+// lhs is paired with the first identical element of rhs not paired yet. ok is false if the
+// types of the slices differ.
+func newUnorderedSlice(c config, lhs, rhs interface{}, visited *visited) (d Differ, ok bool, err error) {
+	if typesDiffer, err := sliceTypesDiffer(lhs, rhs); err != nil || typesDiffer {
+		return nil, false, nil
+	}
+
+	lhsVal := reflect.ValueOf(lhs)
+	rhsVal := reflect.ValueOf(rhs)
+
+	s := slice{
+		lhs: lhs,
+		rhs: rhs,
+	}
+	matched := make([]bool, rhsVal.Len())
+	for i := 0; i < lhsVal.Len(); i++ {
+		s.indices = append(s.indices, i)
+
+		j, d, err := findIdentical(c.at("["+strconv.Itoa(i)+"]"), lhsVal.Index(i), rhsVal, matched, visited)
+		if err != nil {
+			return s, true, err
+		}
+		s.pairs = append(s.pairs, j)
+		if j == -1 {
+			s.diffs = append(s.diffs, sliceMissing{lhsVal.Index(i).Interface()})
+			continue
+		}
+		matched[j] = true
+		s.diffs = append(s.diffs, d)
+	}
+	for j := 0; j < rhsVal.Len(); j++ {
+		if matched[j] {
+			continue
+		}
+		s.indices = append(s.indices, j)
+		s.pairs = append(s.pairs, j)
+		s.diffs = append(s.diffs, sliceExcess{rhsVal.Index(j).Interface()})
+	}
+
+	return s, true, nil
+}
+
+// findIdentical returns the index of the first element of rhs identical to lhs and not matched
+// yet, along with their diff. The index is -1 if no such element exists.
+func findIdentical(c config, lhs, rhs reflect.Value, matched []bool, visited *visited) (int, Differ, error) {
+	for j := 0; j < rhs.Len(); j++ {
+		if matched[j] {
+			continue
+		}
+		d, err := diff(c, lhs.Interface(), rhs.Index(j).Interface(), visited)
+		if err != nil {
+			return -1, nil, err
+		}
+		if d.Diff() == Identical {
+			return j, d, nil
+		}
+	}
+
+	return -1, nil, nil
+}
@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnorderedSlices(t *testing.T) {
+	for _, test := range []struct {
+		LHS   interface{}
+		RHS   interface{}
+		Globs []string
+		Want  Type
+		Paths []string
+	}{
+		{
+			LHS:  []int{1, 2, 3},
+			RHS:  []int{3, 1, 2},
+			Want: Identical,
+		},
+		{
+			LHS:   []string{"a", "a", "b"},
+			RHS:   []string{"b", "a", "b"},
+			Want:  ContentDiffer,
+			Paths: []string{"[1]", "[2]"},
+		},
+		{
+			LHS:  []interface{}{[]interface{}{1, 2}, []interface{}{3}},
+			RHS:  []interface{}{[]interface{}{3}, []interface{}{2, 1}},
+			Want: Identical,
+		},
+		{
+			LHS:  []int{1, 2},
+			RHS:  []float64{2, 1},
+			Want: TypesDiffer,
+		},
+		{
+			LHS: map[string]interface{}{
+				"tags": []interface{}{"a", "b"},
+				"list": []interface{}{"a", "b"},
+			},
+			RHS: map[string]interface{}{
+				"tags": []interface{}{"b", "a"},
+				"list": []interface{}{"b", "a"},
+			},
+			Globs: []string{".tags[]"},
+			Want:  ContentDiffer,
+			Paths: []string{".list[0]", ".list[1]"},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, UnorderedSlices(test.Globs...))
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v, UnorderedSlices(%q)): unexpected error: %s", test.LHS, test.RHS, test.Globs, err)
+			continue
+		}
+		if d.Diff() != test.Want {
+			t.Errorf("Diff(%+v, %+v, UnorderedSlices(%q)) = %s, expected %s", test.LHS, test.RHS, test.Globs, d.Diff(), test.Want)
+		}
+
+		got, err := changedPaths(d)
+		if err != nil {
+			t.Errorf("Walk(Diff(%+v, %+v, UnorderedSlices(%q))): unexpected error: %s", test.LHS, test.RHS, test.Globs, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf(
+				"Diff(%+v, %+v, UnorderedSlices(%q)): got paths %q, expected %q",
+				test.LHS, test.RHS, test.Globs, got, test.Paths,
+			)
+		}
+	}
+
+	_, err := Diff([]int{}, []int{}, UnorderedSlices(".tags["))
+	if err == nil {
+		t.Error("Diff(UnorderedSlices(\".tags[\")): expected error, got nil")
+	}
+}
+
+func TestUnorderedSlicesApply(t *testing.T) {
+	lhs := []interface{}{"a", "b", "c", "b"}
+	rhs := []interface{}{"d", "b", "a", "c"}
+	want := []interface{}{"a", "b", "c", "d"}
+
+	d, err := Diff(lhs, rhs, UnorderedSlices())
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	got, err := Apply(d, lhs)
+	if err != nil {
+		t.Fatalf("Apply: unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply() = %+v, expected %+v", got, want)
+	}
+}
+
+func TestUnorderedSlicesInvert(t *testing.T) {
+	lhs := []interface{}{1, 2, 4}
+	rhs := []interface{}{3, 1}
+
+	d, err := Diff(lhs, rhs, UnorderedSlices())
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+	inverted := Invert(d)
+
+	got, err := Apply(inverted, rhs)
+	if err != nil {
+		t.Fatalf("Apply: unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(got, lhs) {
+		t.Errorf("Apply(Invert()) = %+v, expected %+v", got, lhs)
+	}
+
+	ops, err := JSONPatch(inverted)
+	if err != nil {
+		t.Fatalf("JSONPatch: unexpected error: %s", err)
+	}
+	want := []PatchOperation{
+		{Op: PatchRemove, Path: "/0"},
+		{Op: PatchAdd, Path: "/1", Value: 2},
+		{Op: PatchAdd, Path: "/2", Value: 4},
+	}
+	if !reflect.DeepEqual(ops, want) {
+		t.Errorf("JSONPatch(Invert()) = %+v, expected %+v", ops, want)
+	}
+}
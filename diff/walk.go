@@ -6,12 +6,22 @@ package diff
 // over if possible).
 type WalkFn func(parent Differ, diff Differ, path string) (Differ, error)
 
+// WalkFnV2 is similar to WalkFn, but receives the path of the current node as a Path.
+type WalkFnV2 func(parent Differ, diff Differ, path Path) (Differ, error)
+
 // Walker is implemented by types that can be walked (such as maps and slices)
 type Walker interface {
 	// Walk receives its own path and the walking function.
 	Walk(path string, fn WalkFn) error
 }
 
+// elementWalker is implemented by the Walkers of this package.
+type elementWalker interface {
+	// walkElements calls fn for each child along with its path element, replacing the
+	// children for which fn returns a non-nil Differ.
+	walkElements(fn func(elem PathElement, diff Differ) (Differ, error)) error
+}
+
 // Walk allows to descend a diff tree and replace/edit its leaves and branches.
 // When fn returns a non-nil Differ, the current node is replaced
 // and the new node is walked over (if walkable).
@@ -36,3 +46,35 @@ func walk(parent Differ, diff Differ, path string, fn WalkFn) (Differ, error) {
 
 	return diff, nil
 }
+
+// walkString returns the function used by the Walk method of elementWalkers.
+func walkString(parent Differ, path string, fn WalkFn) func(PathElement, Differ) (Differ, error) {
+	return func(elem PathElement, diff Differ) (Differ, error) {
+		return walk(parent, diff, path+elem.String(), fn)
+	}
+}
+
+// WalkV2 is similar to Walk, but passes the path of the nodes to fn as a Path.
+// Walkers defined outside of this package are not descended.
+func WalkV2(diff Differ, fn WalkFnV2) (Differ, error) {
+	return walkV2(nil, diff, Path{}, fn)
+}
+
+func walkV2(parent Differ, diff Differ, path Path, fn WalkFnV2) (Differ, error) {
+	newD, err := fn(parent, diff, path)
+	if err != nil {
+		return diff, err
+	}
+	if newD != nil {
+		diff = newD
+	}
+
+	walker, ok := diff.(elementWalker)
+	if !ok {
+		return diff, nil
+	}
+
+	return diff, walker.walkElements(func(elem PathElement, d Differ) (Differ, error) {
+		return walkV2(diff, d, path.append(elem), fn)
+	})
+}
@@ -2,6 +2,7 @@ package diff
 
 import (
 	"errors"
+	"reflect"
 	"testing"
 )
 
@@ -60,6 +61,104 @@ func TestWalk(t *testing.T) {
 	}
 }
 
+func TestWalkV2(t *testing.T) {
+	for _, test := range []struct {
+		LHS  interface{}
+		RHS  interface{}
+		Opts []ConfigOpt
+	}{
+		{LHS: 42, RHS: 43},
+		{
+			LHS: map[string]interface{}{
+				"a":   []interface{}{1, map[string]interface{}{"b.c": 2}},
+				"d/e": nil,
+			},
+			RHS: map[string]interface{}{
+				"a":   []interface{}{1, map[string]interface{}{"b.c": 3}, 4},
+				"f~g": "h",
+			},
+		},
+		{
+			LHS: structA{Foo: 42, Bar: subStruct{A: 2}},
+			RHS: structB{Foo: 23, Bar: subStruct{A: 4}},
+		},
+		{
+			LHS: mockStream(1, 2, 3),
+			RHS: mockStream(1, 2, 3, 4),
+		},
+		{
+			LHS:  []interface{}{map[string]interface{}{"id": 1, "v": "a"}},
+			RHS:  []interface{}{map[string]interface{}{"id": 2}, map[string]interface{}{"id": 1, "v": "b"}},
+			Opts: []ConfigOpt{KeyedSlices("id")},
+		},
+	} {
+		d, err := Diff(test.LHS, test.RHS, test.Opts...)
+		if err != nil {
+			t.Errorf("Diff(%+v, %+v): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		var want []string
+		_, err = Walk(d, func(_, _ Differ, path string) (Differ, error) {
+			want = append(want, path)
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("Walk(Diff(%+v, %+v)): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+
+		var got []string
+		_, err = WalkV2(d, func(_, _ Differ, path Path) (Differ, error) {
+			got = append(got, path.String())
+			return nil, nil
+		})
+		if err != nil {
+			t.Errorf("WalkV2(Diff(%+v, %+v)): unexpected error: %s", test.LHS, test.RHS, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("WalkV2(Diff(%+v, %+v)): got paths %q, expected %q", test.LHS, test.RHS, got, want)
+		}
+	}
+}
+
+func TestWalkV2Replace(t *testing.T) {
+	d, err := Diff(
+		map[string]interface{}{"a": []interface{}{1, 2}, "b": 3},
+		map[string]interface{}{"a": []interface{}{1, 4}, "b": 5},
+	)
+	if err != nil {
+		t.Fatalf("Diff: unexpected error: %s", err)
+	}
+
+	var pointers []string
+	d, err = WalkV2(d, func(_, diff Differ, path Path) (Differ, error) {
+		if _, ok := diff.(scalar); ok && diff.Diff() != Identical {
+			pointers = append(pointers, path.JSONPointer())
+			if path.JSONPath() == "$.b" {
+				return Ignore()
+			}
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("WalkV2: unexpected error: %s", err)
+	}
+
+	want := []string{"/a/1", "/b"}
+	if !reflect.DeepEqual(pointers, want) {
+		t.Errorf("WalkV2: got pointers %q, expected %q", pointers, want)
+	}
+	paths, err := changedPaths(d)
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(paths, []string{".a[1]"}) {
+		t.Errorf("WalkV2: got changed paths %q after ignoring .b, expected %q", paths, []string{".a[1]"})
+	}
+}
+
 func TestWalkError(t *testing.T) {
 	var expectedErr = errors.New("forbidden 42")
 	for _, test := range []struct {
@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/BurntSushi/toml"
+	"github.com/fxamacker/cbor"
+	"github.com/vmihailenco/msgpack/v4"
+	"github.com/yazgazan/jaydiff/diff"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	formatJSON = "json"
+	formatYAML = "yaml"
+	formatTOML = "toml"
+	formatXML  = "xml"
+	formatCSV  = "csv"
+	formatTSV  = "tsv"
+
+	formatMsgpack = "msgpack"
+	formatCBOR    = "cbor"
+)
+
+// fileFormat returns the format of fname, detecting it from the file's extension
+// if format is empty. Files with an unknown extension are read as JSON.
+func fileFormat(fname, format string) string {
+	if format != "" {
+		return format
+	}
+	if format, ok := extensionFormat(fname); ok {
+		return format
+	}
+
+	return formatJSON
+}
+
+// extensionFormat returns the format matching the extension of fname, if any.
+func extensionFormat(fname string) (string, bool) {
+	switch strings.ToLower(filepath.Ext(fname)) {
+	case ".json":
+		return formatJSON, true
+	case ".yaml", ".yml":
+		return formatYAML, true
+	case ".toml":
+		return formatTOML, true
+	case ".xml":
+		return formatXML, true
+	case ".csv":
+		return formatCSV, true
+	case ".tsv":
+		return formatTSV, true
+	case ".msgpack", ".mpk":
+		return formatMsgpack, true
+	case ".cbor":
+		return formatCBOR, true
+	}
+
+	return "", false
+}
+
+// decode decodes b. Numbers are decoded as json.Number if useNumber is true (see normalize).
+func decode(format string, b []byte, useNumber bool) (interface{}, error) {
+	switch format {
+	case formatYAML, formatMsgpack, formatCBOR:
+		return decodeDocuments(format, b, useNumber)
+	case formatTOML:
+		return decodeTOML(b, useNumber)
+	case formatXML:
+		return decodeXML(b)
+	case formatCSV:
+		return decodeCSV(b, ',')
+	case formatTSV:
+		return decodeCSV(b, '\t')
+	}
+
+	return decodeJSON(b, useNumber)
+}
+
+func decodeStream(format string, r io.Reader, lineByLine, useNumber bool) (diff.Stream, error) {
+	switch format {
+	case formatYAML, formatMsgpack, formatCBOR:
+		return newDecoderStream(format, r, useNumber), nil
+	case formatTOML, formatXML, formatCSV, formatTSV:
+		return nil, fmt.Errorf("streams are not supported for the %s format", format)
+	}
+
+	if lineByLine {
+		return &LineByLineJSONStream{
+			Scanner:   bufio.NewScanner(r),
+			UseNumber: useNumber,
+		}, nil
+	}
+
+	dec := json.NewDecoder(r)
+	if useNumber {
+		dec.UseNumber()
+	}
+
+	return &diff.JSONStream{
+		Decoder: dec,
+	}, nil
+}
+
+// decodeDocuments decodes a YAML, MessagePack or CBOR file. Files containing
+// multiple documents are decoded as a stream (see documentStreams).
+func decodeDocuments(format string, b []byte, useNumber bool) (interface{}, error) {
+	var docs []interface{}
+
+	s := newDecoderStream(format, bytes.NewReader(b), useNumber)
+	for s.More() {
+		v, err := s.NextValue()
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, v)
+	}
+	if s.err != io.EOF {
+		return nil, s.err
+	}
+
+	switch len(docs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return docs[0], nil
+	}
+
+	return &documentStream{docs: docs}, nil
+}
+
+// documentStream is a stream of decoded documents.
+type documentStream struct {
+	docs []interface{}
+}
+
+// NextValue returns the next document in the stream, or io.EOF.
+func (s *documentStream) NextValue() (interface{}, error) {
+	if len(s.docs) == 0 {
+		return nil, io.EOF
+	}
+	v := s.docs[0]
+	s.docs = s.docs[1:]
+
+	return v, nil
+}
+
+// More reports whether there is another document in the stream.
+func (s *documentStream) More() bool {
+	return len(s.docs) != 0
+}
+
+// documentStreams makes sure lhs and rhs are compared the same way when one of them is a
+// stream (i.e a file containing multiple documents), turning the other one into a stream of a
+// single document.
+func documentStreams(lhs, rhs interface{}) (interface{}, interface{}) {
+	_, lhsStream := lhs.(diff.Stream)
+	_, rhsStream := rhs.(diff.Stream)
+
+	switch {
+	case lhsStream && !rhsStream:
+		rhs = &documentStream{docs: []interface{}{rhs}}
+	case rhsStream && !lhsStream:
+		lhs = &documentStream{docs: []interface{}{lhs}}
+	}
+
+	return lhs, rhs
+}
+
+// decodeTOML decodes a TOML document. Datetimes are converted to RFC 3339 strings.
+func decodeTOML(b []byte, useNumber bool) (interface{}, error) {
+	var v map[string]interface{}
+
+	err := toml.Unmarshal(b, &v)
+
+	return normalize(v, useNumber), err
+}
+
+// decodeXML decodes an XML document into maps and slices:
+//   - the document is a map containing the root element
+//   - elements without attributes or child elements are strings containing their text
+//   - other elements are maps, where attributes are prefixed with "@", child elements
+//     are keyed by name (repeated elements are grouped into a slice) and the text is
+//     stored under "#text"
+//
+// Namespaces (and their declarations) are ignored and surrounding whitespaces are trimmed from
+// text nodes. Only whitespaces, comments and processing instructions can follow the root element.
+func decodeXML(b []byte) (interface{}, error) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil, errors.New("no root element found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			v, err := decodeXMLElement(d, start)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{start.Name.Local: v}, checkXMLEnd(d)
+		}
+	}
+}
+
+// checkXMLEnd checks that nothing but whitespaces, comments and processing instructions follow
+// the root element.
+func checkXMLEnd(d *xml.Decoder) error {
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch tok := tok.(type) {
+		case xml.Comment, xml.ProcInst:
+		case xml.CharData:
+			if len(bytes.TrimSpace(tok)) != 0 {
+				return fmt.Errorf("unexpected text after the root element: %q", tok)
+			}
+		case xml.StartElement:
+			return fmt.Errorf("unexpected element <%s> after the root element", tok.Name.Local)
+		default:
+			return errors.New("unexpected content after the root element")
+		}
+	}
+}
+
+func decodeXMLElement(d *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	var text strings.Builder
+
+	m := make(map[string]interface{})
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		m["@"+attr.Name.Local] = attr.Value
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			v, err := decodeXMLElement(d, tok)
+			if err != nil {
+				return nil, err
+			}
+			addXMLChild(m, tok.Name.Local, v)
+		case xml.CharData:
+			text.Write(tok)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m["#text"] = s
+			}
+			return m, nil
+		}
+	}
+}
+
+func addXMLChild(m map[string]interface{}, name string, v interface{}) {
+	existing, ok := m[name]
+	if !ok {
+		m[name] = v
+		return
+	}
+
+	// Elements are decoded as maps or strings, slices are only used for repeated elements.
+	if s, ok := existing.([]interface{}); ok {
+		m[name] = append(s, v)
+		return
+	}
+	m[name] = []interface{}{existing, v}
+}
+
+// decodeCSV decodes a table into a slice of rows, each row being a map of cells keyed by
+// column name (as defined by the header).
+func decodeCSV(b []byte, comma rune) (interface{}, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comma = comma
+	r.LazyQuotes = comma == '\t'
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return []interface{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []interface{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+}
+
+type valueDecoder interface {
+	Decode(v interface{}) error
+}
+
+// DecoderStream reads the consecutive documents of a YAML, MessagePack or CBOR
+// file as a stream.
+type DecoderStream struct {
+	Decoder   valueDecoder
+	Format    string
+	UseNumber bool
+
+	next   interface{}
+	err    error
+	peeked bool
+}
+
+func newDecoderStream(format string, r io.Reader, useNumber bool) *DecoderStream {
+	var d valueDecoder
+
+	switch format {
+	case formatMsgpack:
+		d = msgpack.NewDecoder(r)
+	case formatCBOR:
+		d = cbor.NewDecoder(r)
+	default:
+		d = yaml.NewDecoder(r)
+	}
+
+	return &DecoderStream{
+		Decoder:   d,
+		Format:    format,
+		UseNumber: useNumber,
+	}
+}
+
+// More reports whether there is another document in the stream.
+func (s *DecoderStream) More() bool {
+	s.peek()
+
+	return s.err == nil
+}
+
+// NextValue returns the next document in the stream, or io.EOF.
+func (s *DecoderStream) NextValue() (interface{}, error) {
+	s.peek()
+	if s.err == nil {
+		s.peeked = false
+	}
+
+	return s.next, s.err
+}
+
+func (s *DecoderStream) peek() {
+	if s.peeked {
+		return
+	}
+
+	var v interface{}
+
+	s.err = s.Decoder.Decode(&v)
+	if s.err != nil && s.err != io.EOF {
+		s.err = fmt.Errorf("decoding %s document: %v", s.Format, s.err)
+	}
+	s.next = normalize(v, s.UseNumber)
+	s.peeked = true
+}
+
+// normalize converts decoded values to the types produced by encoding/json
+// (map[string]interface{}, []interface{} and float64) so that diffs and paths
+// are consistent across input formats. Byte strings are kept as []byte.
+// When useNumber is true, numbers are converted to json.Number instead of
+// float64 (as with json.Decoder.UseNumber), keeping 64-bit integers intact.
+// Infinities and NaNs, having no JSON representation, are kept as float64.
+func normalize(v interface{}, useNumber bool) interface{} {
+	switch v := v.(type) {
+	case nil, string, bool, []byte:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+
+	val := reflect.ValueOf(v)
+	if useNumber {
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return json.Number(strconv.FormatInt(val.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return json.Number(strconv.FormatUint(val.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			f := val.Float()
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				return f
+			}
+			return json.Number(strconv.FormatFloat(f, 'g', -1, val.Type().Bits()))
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Map:
+		m := make(map[string]interface{}, val.Len())
+		for _, key := range val.MapKeys() {
+			m[normalizeKey(key.Interface())] = normalize(val.MapIndex(key).Interface(), useNumber)
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		s := make([]interface{}, val.Len())
+		for i := range s {
+			s[i] = normalize(val.Index(i).Interface(), useNumber)
+		}
+		return s
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint())
+	case reflect.Float32, reflect.Float64:
+		return val.Float()
+	}
+
+	return v
+}
+
+// normalizeKey converts a map key to a string. Binary keys are rendered as
+// hexadecimal.
+func normalizeKey(key interface{}) string {
+	switch key := key.(type) {
+	case string:
+		if utf8.ValidString(key) {
+			return key
+		}
+		return "0x" + hex.EncodeToString([]byte(key))
+	case []byte:
+		return "0x" + hex.EncodeToString(key)
+	}
+
+	return fmt.Sprintf("%v", normalize(key, false))
+}
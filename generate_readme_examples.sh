@@ -30,6 +30,40 @@ $ jaydiff --report --show-types old.json new.json
 $(./jaydiff --report --indent='    ' --show-types test_files/lhs.json test_files/rhs.json)
 $(echo '```')
 
+JSON Patch ([RFC 6902](https://tools.ietf.org/html/rfc6902)):
+
+$(echo '```')
+$ jaydiff --output=jsonpatch --indent='  ' old.json new.json
+
+$(./jaydiff --output=jsonpatch --indent='  ' test_files/lhs.json test_files/rhs.json)
+$(echo '```')
+
+Applying a JSON Patch (as generated by \`--output=jsonpatch\`) to a file. Any RFC 6902 patch using the \`add\`,
+\`remove\`, \`replace\` and \`move\` operations can be applied:
+
+$(echo '```')
+$ jaydiff --output=jsonpatch old.json new.json > changes.json
+$ jaydiff patch --indent='  ' old.json changes.json
+
+$(./jaydiff patch --indent='  ' test_files/lhs.json <(./jaydiff --output=jsonpatch test_files/lhs.json test_files/rhs.json))
+$(echo '```')
+
+Three-way merge (conflicting paths are reported on stderr and the value from OURS is kept):
+
+$(echo '```')
+$ jaydiff merge --indent='  ' base.json ours.json theirs.json
+
+$(./jaydiff merge --indent='  ' test_files/base_merge.json test_files/ours_merge.json test_files/theirs_merge.json)
+$(echo '```')
+
+JSON Merge Patch ([RFC 7396](https://tools.ietf.org/html/rfc7396)):
+
+$(echo '```')
+$ jaydiff --output=mergepatch --indent='  ' old.json new.json
+
+$(./jaydiff --output=mergepatch --indent='  ' test_files/lhs.json test_files/rhs.json)
+$(echo '```')
+
 JSON-like format:
 
 $(echo '```diff')
@@ -70,5 +104,98 @@ $ jaydiff --ignore-excess --ignore-values --stream-validate --report --show-type
 $(./jaydiff --ignore-excess --ignore-values --stream-validate --report --show-types test_files/lhs_validate.json test_files/rhs_validate.json)
 $(echo '```')
 
+Detecting moved array elements (using \`--slice-myers\`, moves are also reported by \`--output=jsonpatch\`):
+
+$(echo '```diff')
+$ jaydiff --slice-myers --report old.json new.json
+
+$(./jaydiff --slice-myers --report test_files/lhs_steps.json test_files/rhs_steps.json)
+$(echo '```')
+
+With \`--slice-myers\`, removed and inserted array elements that are mostly identical are compared in place:
+
+$(echo '```diff')
+$ jaydiff --slice-myers --report old.json new.json
+
+$(./jaydiff --slice-myers --report test_files/lhs_jobs.json test_files/rhs_jobs.json)
+$(echo '```')
+
+Ignoring small differences between floating point numbers (\`--float-rel-tolerance\` is also available):
+
+$(echo '```diff')
+$ jaydiff --report --float-tolerance=0.0001 old.json new.json
+
+$(./jaydiff --report --float-tolerance=0.0001 test_files/lhs_metrics.json test_files/rhs_metrics.json)
+$(echo '```')
+
+Comparing numbers without loss of precision (\`1.0\` and \`1\` are still equal):
+
+$(echo '```diff')
+$ jaydiff --report --use-number old.json new.json
+
+$(./jaydiff --report --use-number test_files/lhs_ids.json test_files/rhs_ids.json)
+$(echo '```')
+
+Showing the changes within strings on a single line (highlighted when the output is a terminal, strings
+containing newlines being quoted):
+
+$(echo '```diff')
+$ jaydiff --report --inline-strings old.json new.json
+
+$(./jaydiff --report --inline-strings test_files/lhs_query.json test_files/rhs_query.json)
+$(echo '```')
+
+Showing the changes within multiline strings as unified diff hunks (not available with \`--json\`):
+
+$(echo '```diff')
+$ jaydiff --report --multiline-strings old.json new.json
+
+$(./jaydiff --report --multiline-strings test_files/lhs_script.json test_files/rhs_script.json)
+$(echo '```')
+
+Ignoring the values matched by a JSONPath query (supporting wildcards, recursive descent, slices and filters):
+
+$(echo '```diff')
+$ jaydiff --report --ignore-query '\$.users[?(@.id == 2)]' old.json new.json
+
+$(./jaydiff --report --ignore-query '$.users[?(@.id == 2)]' test_files/lhs_users.json test_files/rhs_users.json)
+$(echo '```')
+
+Matching array elements by key instead of position (\`--array-key PATH=KEY\`, can be repeated):
+
+$(echo '```diff')
+$ jaydiff --report --array-key '.users[]=id' old.json new.json
+
+$(./jaydiff --report --array-key '.users[]=id' test_files/lhs_users.json test_files/rhs_users.json)
+$(echo '```')
+
+Ignoring the order of array elements (\`--unordered\` applies to all arrays):
+
+$(echo '```diff')
+$ jaydiff --report --unordered='.tags[]' old.json new.json
+
+$(./jaydiff --report --unordered='.tags[]' test_files/lhs_unordered.json test_files/rhs_unordered.json)
+$(echo '```')
+
+Comparing directories (files are paired by relative path):
+
+$(echo '```diff')
+$ jaydiff --recursive --report old new
+
+$(cd test_files && ../jaydiff --recursive --report lhs_dir rhs_dir | sed 's/lhs_dir/old/g; s/rhs_dir/new/g')
+$(echo '```')
+
+Files that cannot be read or parsed are reported and counted as failed, the other files being compared
+as usual. jaydiff then exits with the status of the first failure. Files with an unknown extension (unless
+the format is set using \`--format\`) and symbolic links are skipped with a notice.
+
+Reading one of the files from stdin (using \`-\` as the file name):
+
+$(echo '```diff')
+$ curl -s https://example.com/config.json | jaydiff --report - expected.json
+
+$(./jaydiff --report - test_files/rhs.json < test_files/lhs.json)
+$(echo '```')
+
 
 EOF
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/yazgazan/jaydiff/diff"
+)
+
+// cmdGitDriver is the subcommand used as a git external diff driver (see
+// GIT_EXTERNAL_DIFF and the diff.<driver>.command setting in git-config(1)).
+const cmdGitDriver = "git-driver"
+
+// gitMain compares conf.GitPath between the conf.Files.LHS and conf.Files.RHS
+// revisions, as read by the local git binary.
+func gitMain(conf config) {
+	lhs, err := parseRevision(conf.diffOptions, conf.Files.LHS, conf.GitPath, fileFormat(conf.GitPath, conf.LHSFormat))
+	if err != nil {
+		fail(err)
+	}
+	rhs, err := parseRevision(conf.diffOptions, conf.Files.RHS, conf.GitPath, fileFormat(conf.GitPath, conf.RHSFormat))
+	if err != nil {
+		fail(err)
+	}
+
+	d, err := diffValues(conf.diffOptions, lhs, rhs)
+	if err != nil {
+		fail(err)
+	}
+	printDiff(conf.diffOptions, d)
+	if d.Diff() != diff.Identical {
+		os.Exit(statusDiffMismatch)
+	}
+}
+
+// gitDriverMain compares the files given by git to an external diff driver.
+// Nothing is printed when the files are structurally identical. It always
+// exits with status 0 on success, as git stops at the first failing driver.
+func gitDriverMain(conf gitDriverConfig) {
+	lhs, err := parseDriverFile(conf.diffOptions, conf.Files.OldFile, fileFormat(conf.Files.Path, conf.LHSFormat))
+	if err != nil {
+		fail(err)
+	}
+	rhs, err := parseDriverFile(conf.diffOptions, conf.Files.NewFile, fileFormat(conf.Files.Path, conf.RHSFormat))
+	if err != nil {
+		fail(err)
+	}
+
+	d, err := diffValues(conf.diffOptions, lhs, rhs)
+	if err != nil {
+		fail(err)
+	}
+	if d.Diff() == diff.Identical {
+		return
+	}
+	fmt.Printf("jaydiff a/%s b/%s\n", conf.Files.Path, conf.Files.Path)
+	printDiff(conf.diffOptions, d)
+}
+
+// parseRevision parses path at the rev revision.
+func parseRevision(conf diffOptions, rev, path, format string) (interface{}, error) {
+	name := rev + ":" + path
+	b, err := gitShow(rev, path)
+	if err != nil {
+		return nil, errorf(statusReadError, "cannot read %s: %s", name, err)
+	}
+
+	return parseContent(conf, name, format, b)
+}
+
+// parseDriverFile parses fname. git uses /dev/null for the missing side of
+// added and deleted files, which is parsed as nil.
+func parseDriverFile(conf diffOptions, fname, format string) (interface{}, error) {
+	if fname == os.DevNull {
+		return nil, nil
+	}
+	b, err := readFile(fname)
+	if err != nil {
+		return nil, errorf(statusReadError, "cannot read %s", fname)
+	}
+
+	return parseContent(conf, fname, format, b)
+}
+
+func parseContent(conf diffOptions, name, format string, b []byte) (interface{}, error) {
+	if conf.Stream {
+		return readStream(name, format, bytes.NewReader(b), conf.StreamLines, conf.UseNumber)
+	}
+
+	return parseBytes(name, format, b, conf.UseNumber)
+}
+
+// gitShow returns the content of path at the rev revision. rev is resolved to an object name
+// first, so that it can contain colons (i.e `:/fix: typo`). Relative paths are resolved from
+// the current directory.
+func gitShow(rev, path string) ([]byte, error) {
+	oid, err := git("rev-parse", "--verify", "--quiet", rev)
+	if err != nil {
+		return nil, fmt.Errorf("unknown revision %s", rev)
+	}
+	if !filepath.IsAbs(path) {
+		path = "./" + filepath.ToSlash(path)
+	}
+
+	return git("cat-file", "blob", strings.TrimSpace(string(oid))+":"+path)
+}
+
+// git runs the local git binary, returning its output. The error message is read from stderr.
+func git(args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	b, err := cmd.Output()
+	if err != nil && stderr.Len() != 0 {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+	}
+
+	return b, err
+}
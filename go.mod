@@ -1,14 +1,17 @@
 module github.com/yazgazan/jaydiff
 
 require (
+	github.com/BurntSushi/toml v0.3.1
 	github.com/fatih/color v1.7.0
+	github.com/fxamacker/cbor v1.5.1
 	github.com/gobwas/glob v0.2.3
 	github.com/jessevdk/go-flags v1.4.0
 	github.com/mattn/go-colorable v0.0.9 // indirect
 	github.com/mattn/go-isatty v0.0.4 // indirect
 	github.com/mb0/diff v0.0.0-20131118162322-d8d9a906c24d
-	golang.org/x/crypto v0.0.0-20181203042331-505ab145d0a9
-	golang.org/x/sys v0.0.0-20181205085412-a5c9d58dba9a // indirect
+	github.com/vmihailenco/msgpack/v4 v4.3.12
+	golang.org/x/crypto v0.0.0-20190308221718-c2843e01d9a2
+	gopkg.in/yaml.v2 v2.2.2
 )
 
 go 1.10
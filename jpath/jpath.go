@@ -69,7 +69,7 @@ func EscapeKey(v interface{}) string {
 	if !ok {
 		return fmt.Sprintf("%v", v)
 	}
-	if s != "" && !strings.ContainsAny(s, "[].\"") {
+	if s != "" && !strings.ContainsAny(s, "[].\"*") {
 		return s
 	}
 	return fmt.Sprintf("%q", s)
@@ -113,38 +113,92 @@ func getKey(s string, kind reflect.Kind) (reflect.Value, error) {
 	}
 }
 
+// ExecutePath returns the value found at path in i. Pointers and interfaces are followed, and
+// struct fields are found by name or by json tag (paths produced by the diff package use the
+// field names).
+// path can also be a query (see CompileQuery), in which case the first value it matches is
+// returned, or ErrNotFound if it matches nothing. Keys that would be read as part of a query
+// (such as `*`) are quoted by EscapeKey. ErrInvalidPath is returned if path cannot be parsed.
 func ExecutePath(path string, i interface{}) (interface{}, error) {
+	p := &queryParser{s: path}
+	steps, err := p.parseQuery()
+	if err != nil {
+		return nil, ErrInvalidPath
+	}
+
+	elems, ok := literalPath(steps)
+	if !ok {
+		nodes := applySteps(steps, node{value: reflect.ValueOf(i)})
+		if len(nodes) == 0 {
+			return nil, ErrNotFound
+		}
+		return valueInterface(nodes[0].value), nil
+	}
+
+	return executePath(elems, i)
+}
+
+func executePath(elems []pathElement, i interface{}) (interface{}, error) {
 	// TODO(yazgazan): better errors
-	head, tail := Split(path)
-	if head == "" {
+	if len(elems) == 0 {
 		return i, nil
 	}
+	head, tail := elems[0], elems[1:]
 
-	v := reflect.ValueOf(i)
+	v := indirect(reflect.ValueOf(i))
+	if v.Kind() == reflect.Ptr {
+		return nil, ErrNil
+	}
 
-	switch head[0] {
+	switch {
+	case head.isIndex:
+		return executeSlice(head.index, tail, v)
+	case v.Kind() == reflect.Struct:
+		return executeStruct(head.key, tail, v)
 	default:
-		return nil, ErrInvalidPath
-	case '[':
-		return executeSlice(head, tail, v)
-	case '.':
-		return executeMap(head, tail, v)
+		return executeMap(head.key, tail, v)
 	}
 }
 
-func executeSlice(head, tail string, v reflect.Value) (interface{}, error) {
-	if v.Kind() != reflect.Slice {
-		return nil, ErrNotSlice
+// indirect follows the pointers and interfaces until a nil or concrete value is found.
+func indirect(v reflect.Value) reflect.Value {
+	for (v.Kind() == reflect.Interface || v.Kind() == reflect.Ptr) && !v.IsNil() {
+		v = v.Elem()
 	}
-	if v.IsNil() {
-		return nil, ErrNil
+
+	return v
+}
+
+// structField returns the exported field of v named name, or with a json tag named name.
+func structField(v reflect.Value, name string) (reflect.StructField, reflect.Value, bool) {
+	t := v.Type()
+	tagged := -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if f.Name == name {
+			return f, v.Field(i), true
+		}
+		tag := f.Tag.Get("json")
+		if tag != "-" && strings.Split(tag, ",")[0] == name && tagged == -1 {
+			tagged = i
+		}
 	}
-	if head[len(head)-1] != ']' {
-		return nil, ErrInvalidPath
+	if tagged == -1 {
+		return reflect.StructField{}, reflect.Value{}, false
 	}
-	index, err := strconv.Atoi(head[1 : len(head)-1])
-	if err != nil {
-		return nil, err
+
+	return t.Field(tagged), v.Field(tagged), true
+}
+
+func executeSlice(index int, tail []pathElement, v reflect.Value) (interface{}, error) {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, ErrNotSlice
+	}
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return nil, ErrNil
 	}
 	if index >= v.Len() {
 		return nil, ErrOutOfBounds
@@ -153,17 +207,13 @@ func executeSlice(head, tail string, v reflect.Value) (interface{}, error) {
 	if !val.CanInterface() {
 		return nil, ErrInvalidInterface
 	}
-	return ExecutePath(tail, val.Interface())
+	return executePath(tail, val.Interface())
 }
 
-func executeMap(head, tail string, v reflect.Value) (interface{}, error) {
+func executeMap(keyStr string, tail []pathElement, v reflect.Value) (interface{}, error) {
 	if v.Kind() != reflect.Map {
 		return nil, ErrNotMap
 	}
-	keyStr := head[1:]
-	if keyStr == "" {
-		return nil, ErrInvalidPath
-	}
 	key, err := getKey(keyStr, v.Type().Key().Kind())
 	if err != nil {
 		return nil, err
@@ -172,8 +222,22 @@ func executeMap(head, tail string, v reflect.Value) (interface{}, error) {
 		return nil, ErrNil
 	}
 	val := v.MapIndex(key)
+	if !val.IsValid() {
+		return nil, ErrNotFound
+	}
+	if !val.CanInterface() {
+		return nil, ErrInvalidInterface
+	}
+	return executePath(tail, val.Interface())
+}
+
+func executeStruct(name string, tail []pathElement, v reflect.Value) (interface{}, error) {
+	_, val, ok := structField(v, name)
+	if !ok {
+		return nil, ErrNotFound
+	}
 	if !val.CanInterface() {
 		return nil, ErrInvalidInterface
 	}
-	return ExecutePath(tail, val.Interface())
+	return executePath(tail, val.Interface())
 }
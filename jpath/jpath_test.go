@@ -39,6 +39,7 @@ func TestEscapeKey(t *testing.T) {
 		{"42", `42`},
 		{`"foo`, `"\"foo"`},
 		{"[foo]", `"[foo]"`},
+		{"*", `"*"`},
 		{42, "42"},
 	} {
 		got := EscapeKey(test.In)
@@ -103,6 +104,11 @@ func TestExecutePath(t *testing.T) {
 			".foo",
 			42,
 		},
+		{
+			map[string]int{"*": 42, "a": 1},
+			"." + EscapeKey("*"),
+			42,
+		},
 		{
 			map[string][]int{
 				"foo": []int{1, 2, 3},
@@ -123,6 +129,66 @@ func TestExecutePath(t *testing.T) {
 			".foo[0].23",
 			"ha",
 		},
+		{
+			testStruct{Name: "foo", Tags: []string{"a", "b"}},
+			".Tags[1]",
+			"b",
+		},
+		{
+			&testStruct{Name: "foo"},
+			".name",
+			"foo",
+		},
+		{
+			map[string]interface{}{
+				"foo": &testStruct{Child: &testStruct{Name: "bar"}},
+			},
+			".foo.Child.Name",
+			"bar",
+		},
+		{
+			testStruct{Values: map[string]interface{}{"a": [2]int{1, 2}}},
+			".values.a[1]",
+			2,
+		},
+		{
+			testStruct{Embedded: Embedded{ID: 42}},
+			".Embedded.ID",
+			42,
+		},
+		{
+			map[string]interface{}{"a.b": []interface{}{1, 2}},
+			`."a.b"[1]`,
+			2,
+		},
+		{
+			map[string]interface{}{"a": []interface{}{3, 4}},
+			".a[*]",
+			3,
+		},
+		{
+			map[string]interface{}{"a": []interface{}{map[string]interface{}{"t": 5}}},
+			"..t",
+			5,
+		},
+		{
+			[]int{1, 2, 3},
+			"[1:2]",
+			2,
+		},
+		{
+			[]int{1, 2, 3},
+			"[-1]",
+			3,
+		},
+		{
+			map[string]interface{}{"a": []interface{}{
+				map[string]interface{}{"id": 1, "v": "x"},
+				map[string]interface{}{"id": 2, "v": "y"},
+			}},
+			"$.a[?(@.id == 2)].v",
+			"y",
+		},
 	} {
 		got, err := ExecutePath(test.Path, test.I)
 		if err != nil {
@@ -134,3 +200,46 @@ func TestExecutePath(t *testing.T) {
 		}
 	}
 }
+
+type Embedded struct {
+	ID int
+}
+
+type testStruct struct {
+	Embedded
+	Name       string `json:"name"`
+	Tags       []string
+	Child      *testStruct
+	Values     map[string]interface{} `json:"values,omitempty"`
+	Ignored    int                    `json:"-"`
+	unexported int
+}
+
+func TestExecutePathErrors(t *testing.T) {
+	for _, test := range []struct {
+		I    interface{}
+		Path string
+		Err  error
+	}{
+		{testStruct{}, ".Missing", ErrNotFound},
+		{testStruct{}, ".unexported", ErrNotFound},
+		{testStruct{}, ".-", ErrNotFound},
+		{testStruct{}, ".Child.Name", ErrNil},
+		{testStruct{}, "[0]", ErrNotSlice},
+		{testStruct{}, ".Tags[0]", ErrNil},
+		{testStruct{Tags: []string{"a"}}, ".Tags[1]", ErrOutOfBounds},
+		{map[string]interface{}{"a": (*testStruct)(nil)}, ".a.Name", ErrNil},
+		{[]int{1}, ".a", ErrNotMap},
+		{map[string]int{"a": 1}, ".b", ErrNotFound},
+		{map[string]interface{}{"a": map[int]string{}}, ".a.1", ErrNotFound},
+		{[]int{1}, "[*].a", ErrNotFound},
+		{[]int{1}, "[?(@ > 1)]", ErrNotFound},
+		{[]int{1}, "[0", ErrInvalidPath},
+		{[]int{1}, ".a..", ErrInvalidPath},
+	} {
+		_, err := ExecutePath(test.Path, test.I)
+		if err != test.Err {
+			t.Errorf("ExecutePath(%q, %+v): expected error %q, got %v", test.Path, test.I, test.Err, err)
+		}
+	}
+}
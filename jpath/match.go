@@ -0,0 +1,116 @@
+package jpath
+
+import "reflect"
+
+// Matcher matches a query against a tree one node at a time, starting from its root and
+// descending to its children. It allows matching trees that are not available as a single value
+// (see Query.Matcher).
+type Matcher struct {
+	steps []step
+	// states holds the indices of the next steps to apply, one for each way the current node can
+	// be reached
+	states []int
+}
+
+// child is a node as seen by Matcher: its value and its key (or index) in its parent.
+type child struct {
+	value   reflect.Value
+	key     string
+	index   int
+	len     int
+	isIndex bool
+}
+
+// Matcher returns a Matcher positioned at the root of the tree.
+func (q *Query) Matcher() Matcher {
+	return Matcher{steps: q.steps, states: []int{0}}
+}
+
+// Matched reports whether the current node is matched by the query.
+func (m Matcher) Matched() bool {
+	for _, s := range m.states {
+		if s == len(m.steps) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Key returns the Matcher for the value v, found at key in the current node (a map or struct).
+func (m Matcher) Key(key string, v interface{}) Matcher {
+	return m.child(child{value: reflect.ValueOf(v), key: key})
+}
+
+// Index returns the Matcher for the value v, found at index i in the current node (a slice of
+// length n).
+func (m Matcher) Index(i, n int, v interface{}) Matcher {
+	return m.child(child{value: reflect.ValueOf(v), index: i, len: n, isIndex: true})
+}
+
+func (m Matcher) child(c child) Matcher {
+	res := Matcher{steps: m.steps}
+	seen := map[int]bool{}
+	add := func(s int) {
+		if !seen[s] {
+			seen[s] = true
+			res.states = append(res.states, s)
+		}
+	}
+
+	for _, s := range m.states {
+		if s == len(m.steps) {
+			continue
+		}
+		st := m.steps[s]
+		if r, ok := st.(recursiveStep); ok {
+			// descendants of the current node are still candidates
+			add(s)
+			st = r.step
+		}
+		if selects(st, c) {
+			add(s + 1)
+		}
+	}
+
+	return res
+}
+
+// selects reports whether applying s to the parent of c selects c.
+func selects(s step, c child) bool {
+	switch s := s.(type) {
+	case keysStep:
+		if c.isIndex {
+			return false
+		}
+		for _, k := range s {
+			if k == c.key {
+				return true
+			}
+		}
+	case wildcardStep:
+		return true
+	case indicesStep:
+		if !c.isIndex {
+			return false
+		}
+		for _, i := range s {
+			if i == c.index || (i < 0 && i+c.len == c.index) {
+				return true
+			}
+		}
+	case sliceStep:
+		if !c.isIndex {
+			return false
+		}
+		for _, i := range s.indices(c.len) {
+			if i == c.index {
+				return true
+			}
+		}
+	case filterStep:
+		return s.expr.eval(node{value: c.value}).truthy()
+	}
+
+	return false
+}
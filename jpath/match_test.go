@@ -0,0 +1,71 @@
+package jpath
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	doc := map[string]interface{}{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "a", "price": 8.95, "category": "reference"},
+				map[string]interface{}{"title": "b", "price": 12.99, "category": "fiction"},
+				map[string]interface{}{"title": "c", "price": 8.99, "category": "fiction", "isbn": "0-553"},
+			},
+			"bicycle": map[string]interface{}{"color": "red", "price": 19.95},
+		},
+		"a.b": map[string]interface{}{"c d": 1},
+	}
+
+	for _, query := range []string{
+		"$",
+		`."a.b".c d`,
+		".store.book[-1].title",
+		".store.book[0,2]",
+		".store.*",
+		"$..price",
+		"..book[0].title",
+		"..[1:]",
+		".store.book[::-2].title",
+		".store.book[?(@.isbn)].title",
+		`..[?(@.color == "red")]`,
+		"$..*",
+		".store.book[5]",
+	} {
+		q, err := CompileQuery(query)
+		if err != nil {
+			t.Errorf("CompileQuery(%q): unexpected error: %s", query, err)
+			continue
+		}
+
+		got := matchedPaths(q.Matcher(), "", doc, []string{})
+		want := q.Paths(doc)
+		sort.Strings(got)
+		sort.Strings(want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("CompileQuery(%q).Matcher(): matched %q, expected %q", query, got, want)
+		}
+	}
+}
+
+func matchedPaths(m Matcher, path string, v interface{}, res []string) []string {
+	if m.Matched() {
+		res = append(res, path)
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, c := range v {
+			res = matchedPaths(m.Key(k, c), path+"."+EscapeKey(k), c, res)
+		}
+	case []interface{}:
+		for i, c := range v {
+			res = matchedPaths(m.Index(i, len(v), c), path+"["+strconv.Itoa(i)+"]", c, res)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,238 @@
+package jpath
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when resolving or deleting a key (or struct field) that does not exist,
+// or when traversing it without creating the missing containers.
+var ErrNotFound = errors.New("key not found")
+
+// pathElement is a single key or index of a path.
+type pathElement struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// leafFn updates the container holding the last element of a path.
+type leafFn func(container interface{}, elem pathElement) (interface{}, error)
+
+// SetPath sets the value found at path in i (a tree of map[string]interface{} and []interface{}).
+// When create is true, missing keys and indices are created along the path (setting an index past the
+// end of a slice pads it with nils). SetPath returns the updated tree, as slices (or i itself) may be
+// replaced.
+func SetPath(path string, i, value interface{}, create bool) (interface{}, error) {
+	return mutatePath(path, i, value, create, func(container interface{}, elem pathElement) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[elem.key] = value
+			return c, nil
+		case []interface{}:
+			if elem.index >= len(c) && !create {
+				return nil, ErrOutOfBounds
+			}
+			c = padSlice(c, elem.index+1)
+			c[elem.index] = value
+			return c, nil
+		}
+		return nil, containerError(elem)
+	})
+}
+
+// DeletePath removes the value found at path in i, removing the key from maps or the element from
+// slices. It returns the updated tree.
+func DeletePath(path string, i interface{}) (interface{}, error) {
+	return mutatePath(path, i, nil, false, func(container interface{}, elem pathElement) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if _, ok := c[elem.key]; !ok {
+				return nil, ErrNotFound
+			}
+			delete(c, elem.key)
+			return c, nil
+		case []interface{}:
+			if elem.index >= len(c) {
+				return nil, ErrOutOfBounds
+			}
+			return append(c[:elem.index:elem.index], c[elem.index+1:]...), nil
+		}
+		return nil, containerError(elem)
+	})
+}
+
+// InsertPath inserts value at path in i. Elements of slices are inserted before the element found at
+// the index (an index equal to the length of the slice appends the value), and map keys are set as
+// with SetPath. Missing containers are created as described in SetPath. It returns the updated tree.
+func InsertPath(path string, i, value interface{}, create bool) (interface{}, error) {
+	return mutatePath(path, i, value, create, func(container interface{}, elem pathElement) (interface{}, error) {
+		switch c := container.(type) {
+		case map[string]interface{}:
+			c[elem.key] = value
+			return c, nil
+		case []interface{}:
+			if elem.index > len(c) && !create {
+				return nil, ErrOutOfBounds
+			}
+			c = padSlice(c, elem.index)
+			c = append(c, nil)
+			copy(c[elem.index+1:], c[elem.index:])
+			c[elem.index] = value
+			return c, nil
+		}
+		return nil, containerError(elem)
+	})
+}
+
+func mutatePath(path string, i, value interface{}, create bool, fn leafFn) (interface{}, error) {
+	elems, err := parsePath(path)
+	if err != nil {
+		return i, err
+	}
+	if len(elems) == 0 {
+		return value, nil
+	}
+
+	res, err := mutate(elems, i, create, fn)
+	if err != nil {
+		return i, err
+	}
+
+	return res, nil
+}
+
+// mutate applies fn to the container of the last element of elems, returning the updated i.
+func mutate(elems []pathElement, i interface{}, create bool, fn leafFn) (interface{}, error) {
+	elem := elems[0]
+	i, err := container(i, elem, create)
+	if err != nil {
+		return nil, err
+	}
+	if len(elems) == 1 {
+		return fn(i, elem)
+	}
+
+	switch c := i.(type) {
+	case map[string]interface{}:
+		child, ok := c[elem.key]
+		if !ok && !create {
+			return nil, ErrNotFound
+		}
+		child, err = mutate(elems[1:], child, create, fn)
+		if err != nil {
+			return nil, err
+		}
+		c[elem.key] = child
+		return c, nil
+	default:
+		s := i.([]interface{})
+		if elem.index >= len(s) {
+			if !create {
+				return nil, ErrOutOfBounds
+			}
+			s = padSlice(s, elem.index+1)
+		}
+		child, err := mutate(elems[1:], s[elem.index], create, fn)
+		if err != nil {
+			return nil, err
+		}
+		s[elem.index] = child
+		return s, nil
+	}
+}
+
+// container checks that i can hold elem, creating it if i is nil and create is true.
+func container(i interface{}, elem pathElement, create bool) (interface{}, error) {
+	if isNil(i) {
+		if !create {
+			return nil, ErrNil
+		}
+		if elem.isIndex {
+			return []interface{}{}, nil
+		}
+		return map[string]interface{}{}, nil
+	}
+
+	switch i.(type) {
+	case map[string]interface{}:
+		if !elem.isIndex {
+			return i, nil
+		}
+	case []interface{}:
+		if elem.isIndex {
+			return i, nil
+		}
+	}
+
+	return nil, containerError(elem)
+}
+
+func isNil(i interface{}) bool {
+	switch c := i.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return c == nil
+	case []interface{}:
+		return c == nil
+	}
+
+	return false
+}
+
+func containerError(elem pathElement) error {
+	if elem.isIndex {
+		return ErrNotSlice
+	}
+
+	return ErrNotMap
+}
+
+// padSlice extends s with nils up to n elements.
+func padSlice(s []interface{}, n int) []interface{} {
+	for len(s) < n {
+		s = append(s, nil)
+	}
+
+	return s
+}
+
+// parsePath parses a path made of literal keys and indices (i.e `.a["b.c"][3]`).
+func parsePath(path string) ([]pathElement, error) {
+	p := &queryParser{s: path}
+	steps, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	elems, ok := literalPath(steps)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a literal path", ErrInvalidPath, path)
+	}
+
+	return elems, nil
+}
+
+// literalPath returns the keys and indices selected by steps, if they all select a single key or
+// a single non-negative index.
+func literalPath(steps []step) ([]pathElement, bool) {
+	elems := make([]pathElement, 0, len(steps))
+	for _, s := range steps {
+		switch s := s.(type) {
+		case keysStep:
+			if len(s) == 1 {
+				elems = append(elems, pathElement{key: s[0]})
+				continue
+			}
+		case indicesStep:
+			if len(s) == 1 && s[0] >= 0 {
+				elems = append(elems, pathElement{index: s[0], isIndex: true})
+				continue
+			}
+		}
+		return nil, false
+	}
+
+	return elems, true
+}
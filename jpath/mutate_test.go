@@ -0,0 +1,167 @@
+package jpath
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testTree() map[string]interface{} {
+	return map[string]interface{}{
+		"a": []interface{}{1, map[string]interface{}{"b.c": 2}},
+		"d": "e",
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	for _, test := range []struct {
+		Path   string
+		Value  interface{}
+		Create bool
+		Want   interface{}
+		Err    error
+	}{
+		{Path: "", Value: 42, Want: 42},
+		{
+			Path:  ".d",
+			Value: "f",
+			Want:  map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}}, "d": "f"},
+		},
+		{
+			Path:  `.a[1]["b.c"]`,
+			Value: 3,
+			Want:  map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 3}}, "d": "e"},
+		},
+		{
+			Path:  `.a[1]."b.c"`,
+			Value: 3,
+			Want:  map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 3}}, "d": "e"},
+		},
+		{
+			Path:  ".g",
+			Value: true,
+			Want:  map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}}, "d": "e", "g": true},
+		},
+		{
+			Path:   ".a[3]",
+			Value:  4,
+			Create: true,
+			Want:   map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}, nil, 4}, "d": "e"},
+		},
+		{
+			Path:   ".h.i[0].j",
+			Value:  5,
+			Create: true,
+			Want: map[string]interface{}{
+				"a": []interface{}{1, map[string]interface{}{"b.c": 2}},
+				"d": "e",
+				"h": map[string]interface{}{"i": []interface{}{map[string]interface{}{"j": 5}}},
+			},
+		},
+		{Path: ".a[3]", Value: 4, Err: ErrOutOfBounds},
+		{Path: ".h.i", Value: 5, Err: ErrNotFound},
+		{Path: ".d.f", Value: 5, Create: true, Err: ErrNotMap},
+		{Path: ".a.f", Value: 5, Err: ErrNotMap},
+		{Path: ".d[0]", Value: 5, Err: ErrNotSlice},
+		{Path: ".a[*]", Value: 5, Err: ErrInvalidPath},
+	} {
+		got, err := SetPath(test.Path, testTree(), test.Value, test.Create)
+		if !testError(t, "SetPath", test.Path, err, test.Err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("SetPath(%q, ...) = %+v, expected %+v", test.Path, got, test.Want)
+		}
+	}
+}
+
+func TestDeletePath(t *testing.T) {
+	for _, test := range []struct {
+		Path string
+		Want interface{}
+		Err  error
+	}{
+		{Path: "", Want: nil},
+		{Path: ".d", Want: map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}}}},
+		{Path: ".a[0]", Want: map[string]interface{}{"a": []interface{}{map[string]interface{}{"b.c": 2}}, "d": "e"}},
+		{Path: `.a[1]["b.c"]`, Want: map[string]interface{}{"a": []interface{}{1, map[string]interface{}{}}, "d": "e"}},
+		{Path: ".a[2]", Err: ErrOutOfBounds},
+		{Path: ".g", Err: ErrNotFound},
+		{Path: ".g.h", Err: ErrNotFound},
+		{Path: ".d[0]", Err: ErrNotSlice},
+	} {
+		got, err := DeletePath(test.Path, testTree())
+		if !testError(t, "DeletePath", test.Path, err, test.Err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("DeletePath(%q, ...) = %+v, expected %+v", test.Path, got, test.Want)
+		}
+	}
+}
+
+func TestInsertPath(t *testing.T) {
+	for _, test := range []struct {
+		Path   string
+		Value  interface{}
+		Create bool
+		Want   interface{}
+		Err    error
+	}{
+		{
+			Path:  ".a[0]",
+			Value: 0,
+			Want:  map[string]interface{}{"a": []interface{}{0, 1, map[string]interface{}{"b.c": 2}}, "d": "e"},
+		},
+		{
+			Path:  ".a[1]",
+			Value: 0,
+			Want:  map[string]interface{}{"a": []interface{}{1, 0, map[string]interface{}{"b.c": 2}}, "d": "e"},
+		},
+		{
+			Path:  ".a[2]",
+			Value: 0,
+			Want:  map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}, 0}, "d": "e"},
+		},
+		{
+			Path:   ".a[3]",
+			Value:  0,
+			Create: true,
+			Want:   map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}, nil, 0}, "d": "e"},
+		},
+		{
+			Path:   ".f[0]",
+			Value:  0,
+			Create: true,
+			Want:   map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}}, "d": "e", "f": []interface{}{0}},
+		},
+		{
+			Path:  ".d",
+			Value: 0,
+			Want:  map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b.c": 2}}, "d": 0},
+		},
+		{Path: ".a[3]", Value: 0, Err: ErrOutOfBounds},
+		{Path: ".f[0]", Value: 0, Err: ErrNotFound},
+	} {
+		got, err := InsertPath(test.Path, testTree(), test.Value, test.Create)
+		if !testError(t, "InsertPath", test.Path, err, test.Err) {
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("InsertPath(%q, ...) = %+v, expected %+v", test.Path, got, test.Want)
+		}
+	}
+}
+
+// testError reports unexpected errors, returning true if the result of the call is to be checked.
+func testError(t *testing.T, fn, path string, err, want error) bool {
+	switch {
+	case want == nil && err != nil:
+		t.Errorf("%s(%q, ...): unexpected error: %s", fn, path, err)
+	case want == ErrInvalidPath && errors.Is(err, ErrInvalidPath):
+	case want != nil && err != want:
+		t.Errorf("%s(%q, ...): expected error %q, got %v", fn, path, want, err)
+	}
+
+	return want == nil && err == nil
+}
@@ -0,0 +1,902 @@
+package jpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Query is a compiled JSONPath query (see CompileQuery).
+type Query struct {
+	query string
+	steps []step
+}
+
+// node is a value matched by a query, along with its path.
+type node struct {
+	value reflect.Value
+	path  string
+}
+
+// step selects nodes from the children (or descendants) of a node.
+type step interface {
+	apply(n node, res []node) []node
+}
+
+// CompileQuery parses a JSONPath query. On top of the paths produced by jaydiff (i.e `.a["b.c"][3]`),
+// queries support:
+//   - an optional root `$`
+//   - bracketed keys and unions (`['a']`, `["a","b"]`, `[0,2]`)
+//   - wildcards (`.*`, `[*]`)
+//   - recursive descent (`..name`, `..*`, `..[0]`)
+//   - array slices (`[1:3]`, `[::2]`, `[-1:]`)
+//   - filter expressions (`[?(@.type == "x" && @.size > 3)]`), supporting the `==`, `!=`, `<`, `<=`,
+//     `>`, `>=`, `&&`, `||` and `!` operators. A relative path alone tests for existence.
+//
+// Pointers, interfaces and structs are traversed as described in ExecutePath.
+func CompileQuery(query string) (*Query, error) {
+	p := &queryParser{s: query}
+	steps, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Query{query: query, steps: steps}, nil
+}
+
+// ExecuteQuery compiles query and returns the values it matches in i.
+func ExecuteQuery(query string, i interface{}) ([]interface{}, error) {
+	q, err := CompileQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.Execute(i), nil
+}
+
+// String returns the query as passed to CompileQuery.
+func (q *Query) String() string {
+	return q.query
+}
+
+// Execute returns the values matched by q in i. Map keys are visited in sorted order.
+func (q *Query) Execute(i interface{}) []interface{} {
+	nodes := q.nodes(i)
+	res := make([]interface{}, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, valueInterface(n.value))
+	}
+
+	return res
+}
+
+// Paths returns the paths of the values matched by q in i, in the format used by jaydiff
+// (i.e `.a["b.c"][3]`).
+func (q *Query) Paths(i interface{}) []string {
+	nodes := q.nodes(i)
+	res := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		res = append(res, n.path)
+	}
+
+	return res
+}
+
+func (q *Query) nodes(i interface{}) []node {
+	return applySteps(q.steps, node{value: reflect.ValueOf(i)})
+}
+
+func applySteps(steps []step, n node) []node {
+	nodes := []node{n}
+	for _, s := range steps {
+		var res []node
+		for _, n := range nodes {
+			res = s.apply(n, res)
+		}
+		nodes = res
+	}
+
+	return nodes
+}
+
+func valueInterface(v reflect.Value) interface{} {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+
+	return v.Interface()
+}
+
+// children returns the elements of slices and arrays, the values of maps (sorted by key) and the
+// exported fields of structs.
+func children(n node) []node {
+	v := indirect(n.value)
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		res := make([]node, v.Len())
+		for i := range res {
+			res[i] = indexNode(n, v, i)
+		}
+		return res
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%v", keys[i].Interface()) < fmt.Sprintf("%v", keys[j].Interface())
+		})
+		res := make([]node, len(keys))
+		for i, k := range keys {
+			res[i] = node{value: v.MapIndex(k), path: n.path + "." + EscapeKey(k.Interface())}
+		}
+		return res
+	case reflect.Struct:
+		var res []node
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Type().Field(i); f.PkgPath == "" {
+				res = append(res, node{value: v.Field(i), path: n.path + "." + EscapeKey(f.Name)})
+			}
+		}
+		return res
+	}
+
+	return nil
+}
+
+func indexNode(n node, v reflect.Value, i int) node {
+	return node{value: v.Index(i), path: n.path + "[" + strconv.Itoa(i) + "]"}
+}
+
+// keyNode returns the value found at key in n, if n is a map containing key or a struct with a
+// field named key (see ExecutePath).
+func keyNode(n node, key string) (node, bool) {
+	v := indirect(n.value)
+	if v.Kind() == reflect.Struct {
+		f, val, ok := structField(v, key)
+		return node{value: val, path: n.path + "." + EscapeKey(f.Name)}, ok
+	}
+	if v.Kind() != reflect.Map {
+		return node{}, false
+	}
+
+	k, err := getKey(key, v.Type().Key().Kind())
+	if err != nil {
+		return node{}, false
+	}
+	val := v.MapIndex(k.Convert(v.Type().Key()))
+	if !val.IsValid() {
+		return node{}, false
+	}
+
+	return node{value: val, path: n.path + "." + EscapeKey(k.Interface())}, true
+}
+
+type keysStep []string
+
+func (s keysStep) apply(n node, res []node) []node {
+	for _, k := range s {
+		if c, ok := keyNode(n, k); ok {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+type wildcardStep struct{}
+
+func (wildcardStep) apply(n node, res []node) []node {
+	return append(res, children(n)...)
+}
+
+type indicesStep []int
+
+func (s indicesStep) apply(n node, res []node) []node {
+	v := indirect(n.value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return res
+	}
+
+	for _, i := range s {
+		if i < 0 {
+			i += v.Len()
+		}
+		if i >= 0 && i < v.Len() {
+			res = append(res, indexNode(n, v, i))
+		}
+	}
+
+	return res
+}
+
+type sliceStep struct {
+	start, end *int
+	step       int
+}
+
+func (s sliceStep) apply(n node, res []node) []node {
+	v := indirect(n.value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return res
+	}
+
+	for _, i := range s.indices(v.Len()) {
+		res = append(res, indexNode(n, v, i))
+	}
+
+	return res
+}
+
+// indices returns the indices selected by s in a slice of length l.
+func (s sliceStep) indices(l int) []int {
+	var res []int
+
+	bound := func(i *int, def int) int {
+		if i == nil {
+			return def
+		}
+		if *i < 0 {
+			return maxInt(*i+l, -1)
+		}
+		return minInt(*i, l)
+	}
+	if s.step > 0 {
+		start, end := maxInt(bound(s.start, 0), 0), bound(s.end, l)
+		for i := start; i < end; i += s.step {
+			res = append(res, i)
+		}
+		return res
+	}
+	start, end := minInt(bound(s.start, l-1), l-1), bound(s.end, -1)
+	for i := start; i > end; i += s.step {
+		res = append(res, i)
+	}
+
+	return res
+}
+
+type filterStep struct {
+	expr expr
+}
+
+func (s filterStep) apply(n node, res []node) []node {
+	for _, c := range children(n) {
+		if s.expr.eval(c).truthy() {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+// recursiveStep applies its step to a node and all its descendants. Values found again while
+// descending through one of their own children (i.e circular references) are skipped.
+type recursiveStep struct {
+	step step
+}
+
+func (s recursiveStep) apply(n node, res []node) []node {
+	return s.descend(n, res, nil)
+}
+
+// descend applies s to n and its descendants. ancestors holds the pointers of the nodes being
+// descended.
+func (s recursiveStep) descend(n node, res []node, ancestors []uintptr) []node {
+	ptrs := pointers(n.value)
+	for _, p := range ptrs {
+		if inPointers(ancestors, p) {
+			return res
+		}
+	}
+	ancestors = append(ancestors, ptrs...)
+
+	res = s.step.apply(n, res)
+	for _, c := range children(n) {
+		res = s.descend(c, res, ancestors)
+	}
+
+	return res
+}
+
+// pointers returns the addresses of the pointers, maps and slices found while following v (see
+// indirect). Empty maps and slices are skipped, as they cannot hold circular references.
+func pointers(v reflect.Value) []uintptr {
+	var res []uintptr
+	for v.IsValid() {
+		switch v.Kind() {
+		case reflect.Interface:
+			if v.IsNil() {
+				return res
+			}
+			v = v.Elem()
+			continue
+		case reflect.Ptr:
+			if v.IsNil() {
+				return res
+			}
+			res = append(res, v.Pointer())
+			v = v.Elem()
+			continue
+		case reflect.Map, reflect.Slice:
+			if v.Len() != 0 {
+				res = append(res, v.Pointer())
+			}
+		}
+		return res
+	}
+
+	return res
+}
+
+func inPointers(pointers []uintptr, p uintptr) bool {
+	for _, ptr := range pointers {
+		if ptr == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// operand is the result of evaluating a filter expression.
+type operand struct {
+	value   interface{}
+	missing bool
+}
+
+func (o operand) truthy() bool {
+	if o.missing {
+		return false
+	}
+	if b, ok := o.value.(bool); ok {
+		return b
+	}
+
+	return true
+}
+
+type expr interface {
+	eval(n node) operand
+}
+
+type literalExpr struct {
+	value interface{}
+}
+
+func (e literalExpr) eval(node) operand {
+	return operand{value: e.value}
+}
+
+// relativeExpr evaluates a path relative to the current node (`@`), using its first match.
+type relativeExpr struct {
+	steps []step
+}
+
+func (e relativeExpr) eval(n node) operand {
+	nodes := applySteps(e.steps, n)
+	if len(nodes) == 0 {
+		return operand{missing: true}
+	}
+
+	return operand{value: valueInterface(indirect(nodes[0].value))}
+}
+
+type notExpr struct {
+	expr expr
+}
+
+func (e notExpr) eval(n node) operand {
+	return operand{value: !e.expr.eval(n).truthy()}
+}
+
+type logicalExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e logicalExpr) eval(n node) operand {
+	left := e.left.eval(n).truthy()
+	if e.op == "&&" {
+		return operand{value: left && e.right.eval(n).truthy()}
+	}
+
+	return operand{value: left || e.right.eval(n).truthy()}
+}
+
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+func (e compareExpr) eval(n node) operand {
+	left, right := e.left.eval(n), e.right.eval(n)
+	if left.missing || right.missing {
+		return operand{value: false}
+	}
+
+	return operand{value: compare(e.op, left.value, right.value)}
+}
+
+func compare(op string, lhs, rhs interface{}) bool {
+	lhsNum, lhsOk := number(lhs)
+	rhsNum, rhsOk := number(rhs)
+	if lhsOk && rhsOk {
+		return compareOrdered(op, lhsNum < rhsNum, lhsNum == rhsNum)
+	}
+	lhsStr, lhsOk := lhs.(string)
+	rhsStr, rhsOk := rhs.(string)
+	if lhsOk && rhsOk {
+		return compareOrdered(op, lhsStr < rhsStr, lhsStr == rhsStr)
+	}
+
+	switch op {
+	case "==":
+		return comparable(lhs, rhs) && lhs == rhs
+	case "!=":
+		return !comparable(lhs, rhs) || lhs != rhs
+	}
+
+	return false
+}
+
+func compareOrdered(op string, less, equal bool) bool {
+	switch op {
+	case "==":
+		return equal
+	case "!=":
+		return !equal
+	case "<":
+		return less
+	case "<=":
+		return less || equal
+	case ">":
+		return !less && !equal
+	}
+
+	return !less
+}
+
+func comparable(lhs, rhs interface{}) bool {
+	return (lhs == nil || reflect.TypeOf(lhs).Comparable()) && (rhs == nil || reflect.TypeOf(rhs).Comparable())
+}
+
+func number(i interface{}) (float64, bool) {
+	if n, ok := i.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+
+	v := reflect.ValueOf(i)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	return 0, false
+}
+
+type queryParser struct {
+	s   string
+	pos int
+	// filters is the number of filter expressions being parsed
+	filters int
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d in %q", ErrInvalidPath, fmt.Sprintf(format, args...), p.pos, p.s)
+}
+
+func (p *queryParser) parseQuery() ([]step, error) {
+	if strings.HasPrefix(p.s, "$") {
+		p.pos++
+	}
+
+	steps, err := p.parseSteps()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.s) {
+		return nil, p.errorf("unexpected %q", p.s[p.pos])
+	}
+
+	return steps, nil
+}
+
+// parseSteps parses steps until the end of the query or a character that cannot start a step.
+func (p *queryParser) parseSteps() ([]step, error) {
+	var steps []step
+
+	for p.pos < len(p.s) {
+		var (
+			s   step
+			err error
+		)
+
+		switch {
+		case strings.HasPrefix(p.s[p.pos:], ".."):
+			p.pos += 2
+			if p.peek() == '[' {
+				s, err = p.parseBracket()
+			} else {
+				s, err = p.parseDotted()
+			}
+			s = recursiveStep{step: s}
+		case p.peek() == '.':
+			p.pos++
+			s, err = p.parseDotted()
+		case p.peek() == '[':
+			s, err = p.parseBracket()
+		default:
+			return steps, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, s)
+	}
+
+	return steps, nil
+}
+
+func (p *queryParser) peek() byte {
+	if p.pos >= len(p.s) {
+		return 0
+	}
+
+	return p.s[p.pos]
+}
+
+// parseDotted parses the key (or wildcard) following a dot.
+func (p *queryParser) parseDotted() (step, error) {
+	switch p.peek() {
+	case '*':
+		p.pos++
+		return wildcardStep{}, nil
+	case '"':
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return keysStep{key}, nil
+	}
+
+	// keys are delimited by operators and spaces in filter expressions
+	delimiters := ".["
+	if p.filters != 0 {
+		delimiters += "]()=!<>&| "
+	}
+	start := p.pos
+	for p.pos < len(p.s) && !strings.ContainsRune(delimiters, rune(p.s[p.pos])) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, p.errorf("expected a key")
+	}
+
+	return keysStep{p.s[start:p.pos]}, nil
+}
+
+// parseString parses a single or double quoted string.
+func (p *queryParser) parseString() (string, error) {
+	quote := p.peek()
+	start := p.pos
+	for p.pos++; p.pos < len(p.s); p.pos++ {
+		switch p.s[p.pos] {
+		case '\\':
+			p.pos++
+		case quote:
+			p.pos++
+			s := p.s[start:p.pos]
+			if quote == '\'' {
+				s = `"` + strings.Replace(strings.Replace(s[1:len(s)-1], `\'`, `'`, -1), `"`, `\"`, -1) + `"`
+			}
+			unquoted, err := strconv.Unquote(s)
+			if err != nil {
+				return "", p.errorf("invalid string %s", p.s[start:p.pos])
+			}
+			return unquoted, nil
+		}
+	}
+
+	return "", p.errorf("unterminated string")
+}
+
+func (p *queryParser) parseBracket() (step, error) {
+	p.pos++
+	p.skipSpaces()
+
+	var (
+		s   step
+		err error
+	)
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		s = wildcardStep{}
+	case c == '?':
+		s, err = p.parseFilter()
+	case c == '\'' || c == '"':
+		s, err = p.parseKeys()
+	default:
+		s, err = p.parseIndices()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.peek() != ']' {
+		return nil, p.errorf("expected ']'")
+	}
+	p.pos++
+
+	return s, nil
+}
+
+func (p *queryParser) parseKeys() (step, error) {
+	var keys keysStep
+
+	for {
+		p.skipSpaces()
+		if c := p.peek(); c != '\'' && c != '"' {
+			return nil, p.errorf("expected a quoted key")
+		}
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		p.skipSpaces()
+		if p.peek() != ',' {
+			return keys, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *queryParser) parseIndices() (step, error) {
+	var (
+		indices indicesStep
+		bounds  []*int
+	)
+
+	for {
+		p.skipSpaces()
+		i, ok, err := p.parseInt()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+
+		switch {
+		case p.peek() == ':':
+			p.pos++
+			if len(indices) != 0 || len(bounds) == 2 {
+				return nil, p.errorf("invalid slice")
+			}
+			bounds = append(bounds, i)
+		case len(bounds) != 0:
+			return p.sliceStep(append(bounds, i))
+		case !ok:
+			return nil, p.errorf("expected an index")
+		case p.peek() == ',':
+			p.pos++
+			indices = append(indices, *i)
+		default:
+			return append(indices, *i), nil
+		}
+	}
+}
+
+func (p *queryParser) sliceStep(bounds []*int) (step, error) {
+	s := sliceStep{start: bounds[0], end: bounds[1], step: 1}
+	if len(bounds) == 3 && bounds[2] != nil {
+		s.step = *bounds[2]
+	}
+	if s.step == 0 {
+		return nil, p.errorf("slice step cannot be 0")
+	}
+
+	return s, nil
+}
+
+// parseInt parses an optional integer.
+func (p *queryParser) parseInt() (*int, bool, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, false, nil
+	}
+
+	i, err := strconv.Atoi(p.s[start:p.pos])
+	if err != nil {
+		return nil, false, p.errorf("invalid index %q", p.s[start:p.pos])
+	}
+
+	return &i, true, nil
+}
+
+func (p *queryParser) parseFilter() (step, error) {
+	p.pos++
+	if p.peek() != '(' {
+		return nil, p.errorf("expected '(' after '?'")
+	}
+	p.pos++
+
+	p.filters++
+	e, err := p.parseOr()
+	p.filters--
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.peek() != ')' {
+		return nil, p.errorf("expected ')'")
+	}
+	p.pos++
+
+	return filterStep{expr: e}, nil
+}
+
+func (p *queryParser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); strings.HasPrefix(p.s[p.pos:], "||"); p.skipSpaces() {
+		p.pos += 2
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{op: "||", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.skipSpaces(); strings.HasPrefix(p.s[p.pos:], "&&"); p.skipSpaces() {
+		p.pos += 2
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalExpr{op: "&&", left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *queryParser) parseUnary() (expr, error) {
+	p.skipSpaces()
+
+	switch p.peek() {
+	case '!':
+		if strings.HasPrefix(p.s[p.pos:], "!=") {
+			return nil, p.errorf("unexpected '!='")
+		}
+		p.pos++
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: e}, nil
+	case '(':
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return nil, p.errorf("expected ')'")
+		}
+		p.pos++
+		return e, nil
+	}
+
+	return p.parseComparison()
+}
+
+var compareOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func (p *queryParser) parseComparison() (expr, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	for _, op := range compareOps {
+		if !strings.HasPrefix(p.s[p.pos:], op) {
+			continue
+		}
+		p.pos += len(op)
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{op: op, left: left, right: right}, nil
+	}
+
+	return left, nil
+}
+
+func (p *queryParser) parseOperand() (expr, error) {
+	p.skipSpaces()
+
+	switch c := p.peek(); {
+	case c == '@':
+		p.pos++
+		steps, err := p.parseSteps()
+		if err != nil {
+			return nil, err
+		}
+		return relativeExpr{steps: steps}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalExpr{value: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos++; p.pos < len(p.s) && strings.ContainsRune("0123456789.eE+-", rune(p.s[p.pos])); p.pos++ {
+		}
+		f, err := strconv.ParseFloat(p.s[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.s[start:p.pos])
+		}
+		return literalExpr{value: f}, nil
+	}
+
+	for _, lit := range []struct {
+		s string
+		v interface{}
+	}{{"true", true}, {"false", false}, {"null", nil}} {
+		if strings.HasPrefix(p.s[p.pos:], lit.s) {
+			p.pos += len(lit.s)
+			return literalExpr{value: lit.v}, nil
+		}
+	}
+
+	return nil, p.errorf("expected an operand")
+}
+
+func (p *queryParser) skipSpaces() {
+	for p.peek() == ' ' {
+		p.pos++
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
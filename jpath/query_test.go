@@ -0,0 +1,197 @@
+package jpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	doc := map[string]interface{}{
+		"store": map[string]interface{}{
+			"book": []interface{}{
+				map[string]interface{}{"title": "a", "price": 8.95, "category": "reference"},
+				map[string]interface{}{"title": "b", "price": 12.99, "category": "fiction"},
+				map[string]interface{}{"title": "c", "price": 8.99, "category": "fiction", "isbn": "0-553"},
+				map[string]interface{}{"title": "d", "price": json.Number("22.99"), "category": "fiction", "isbn": "0-395"},
+			},
+			"bicycle": map[string]interface{}{"color": "red", "price": 19.95},
+		},
+		"a.b":   map[string]interface{}{"c d": 1, "it's": 2},
+		"count": 4,
+	}
+
+	for _, test := range []struct {
+		Query string
+		Paths []string
+	}{
+		{Query: "", Paths: []string{""}},
+		{Query: "$", Paths: []string{""}},
+		{Query: ".count", Paths: []string{".count"}},
+		{Query: "$.store.bicycle.color", Paths: []string{".store.bicycle.color"}},
+		{Query: `."a.b".c d`, Paths: []string{`."a.b".c d`}},
+		{Query: `$['a.b']['it\'s']`, Paths: []string{`."a.b".it's`}},
+		{Query: `$["a.b"]["c d","it's","missing"]`, Paths: []string{`."a.b".c d`, `."a.b".it's`}},
+		{Query: ".store.book[1].title", Paths: []string{".store.book[1].title"}},
+		{Query: ".store.book[-1].title", Paths: []string{".store.book[3].title"}},
+		{Query: ".store.book[0,2].title", Paths: []string{".store.book[0].title", ".store.book[2].title"}},
+		{Query: ".store.book[5]", Paths: []string{}},
+		{Query: ".store.*", Paths: []string{".store.bicycle", ".store.book"}},
+		{
+			Query: ".store.book[*].title",
+			Paths: []string{".store.book[0].title", ".store.book[1].title", ".store.book[2].title", ".store.book[3].title"},
+		},
+		{
+			Query: "$..price",
+			Paths: []string{
+				".store.bicycle.price",
+				".store.book[0].price",
+				".store.book[1].price",
+				".store.book[2].price",
+				".store.book[3].price",
+			},
+		},
+		{Query: "..isbn", Paths: []string{".store.book[2].isbn", ".store.book[3].isbn"}},
+		{Query: "..book[0].title", Paths: []string{".store.book[0].title"}},
+		{Query: ".store.book[1:3].title", Paths: []string{".store.book[1].title", ".store.book[2].title"}},
+		{Query: ".store.book[:2].title", Paths: []string{".store.book[0].title", ".store.book[1].title"}},
+		{Query: ".store.book[-2:].title", Paths: []string{".store.book[2].title", ".store.book[3].title"}},
+		{Query: ".store.book[::2].title", Paths: []string{".store.book[0].title", ".store.book[2].title"}},
+		{Query: ".store.book[::-3].title", Paths: []string{".store.book[3].title", ".store.book[0].title"}},
+		{Query: ".store.book[?(@.isbn)].title", Paths: []string{".store.book[2].title", ".store.book[3].title"}},
+		{Query: ".store.book[?(!@.isbn)].title", Paths: []string{".store.book[0].title", ".store.book[1].title"}},
+		{Query: `.store.book[?(@.category == "reference")].title`, Paths: []string{".store.book[0].title"}},
+		{Query: ".store.book[?(@.price < 10)].title", Paths: []string{".store.book[0].title", ".store.book[2].title"}},
+		{Query: ".store.book[?(@.price >= 22.99)].title", Paths: []string{".store.book[3].title"}},
+		{
+			Query: `.store.book[?(@.category != 'fiction' || (@.price > 10 && @.isbn))].title`,
+			Paths: []string{".store.book[0].title", ".store.book[3].title"},
+		},
+		{Query: `..[?(@.color == "red")]`, Paths: []string{".store.bicycle"}},
+		{Query: ".store.book[?(@.title > 'b')].title", Paths: []string{".store.book[2].title", ".store.book[3].title"}},
+		{Query: ".store.book[?(@.missing == null)]", Paths: []string{}},
+		{Query: ".count[0]", Paths: []string{}},
+	} {
+		q, err := CompileQuery(test.Query)
+		if err != nil {
+			t.Errorf("CompileQuery(%q): unexpected error: %s", test.Query, err)
+			continue
+		}
+
+		got := q.Paths(doc)
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf("CompileQuery(%q).Paths() = %q, expected %q", test.Query, got, test.Paths)
+		}
+	}
+}
+
+func TestExecuteQuery(t *testing.T) {
+	doc := map[string]interface{}{
+		"users": []interface{}{
+			map[string]interface{}{"name": "alice", "admin": true},
+			map[string]interface{}{"name": "bob", "admin": false},
+		},
+		"ids": map[int][]int{1: {2, 3}},
+	}
+
+	for _, test := range []struct {
+		Query string
+		Want  []interface{}
+	}{
+		{Query: ".users[?(@.admin == true)].name", Want: []interface{}{"alice"}},
+		{Query: ".users[?(@.admin)].name", Want: []interface{}{"alice"}},
+		{Query: ".users[*].name", Want: []interface{}{"alice", "bob"}},
+		{Query: ".ids.1[1]", Want: []interface{}{3}},
+		{Query: ".ids[*][*]", Want: []interface{}{2, 3}},
+	} {
+		got, err := ExecuteQuery(test.Query, doc)
+		if err != nil {
+			t.Errorf("ExecuteQuery(%q): unexpected error: %s", test.Query, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.Want) {
+			t.Errorf("ExecuteQuery(%q) = %+v, expected %+v", test.Query, got, test.Want)
+		}
+	}
+}
+
+func TestQueryStruct(t *testing.T) {
+	doc := &testStruct{
+		Name:  "root",
+		Child: &testStruct{Name: "child", Tags: []string{"a"}},
+	}
+
+	for _, test := range []struct {
+		Query string
+		Paths []string
+	}{
+		{Query: ".name", Paths: []string{".Name"}},
+		{Query: ".Child.Tags[0]", Paths: []string{".Child.Tags[0]"}},
+		{Query: "..Name", Paths: []string{".Name", ".Child.Name"}},
+		{Query: `$[?(@.name == "child")]`, Paths: []string{".Child"}},
+	} {
+		q, err := CompileQuery(test.Query)
+		if err != nil {
+			t.Errorf("CompileQuery(%q): unexpected error: %s", test.Query, err)
+			continue
+		}
+
+		got := q.Paths(doc)
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf("CompileQuery(%q).Paths() = %q, expected %q", test.Query, got, test.Paths)
+		}
+	}
+}
+
+func TestCompileQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"foo",
+		".",
+		".a[",
+		".a[1",
+		".a[x]",
+		".a['b",
+		".a[1:2:0]",
+		".a[?@.b]",
+		".a[?(@.b ==)]",
+		".a[?(@.b == 1]",
+		".a[1,2:3]",
+		"$$",
+	} {
+		_, err := CompileQuery(query)
+		if err == nil {
+			t.Errorf("CompileQuery(%q): expected an error", query)
+		}
+	}
+}
+
+func TestQueryCyclic(t *testing.T) {
+	s := &testStruct{Name: "root"}
+	s.Child = &testStruct{Name: "child", Child: s}
+	m := map[string]interface{}{"name": "root"}
+	m["self"] = m
+	l := []interface{}{"a", nil}
+	l[1] = l
+
+	for _, test := range []struct {
+		I     interface{}
+		Query string
+		Paths []string
+	}{
+		{I: s, Query: "$..Name", Paths: []string{".Name", ".Child.Name"}},
+		{I: s, Query: `$..[?(@.name == "root")]`, Paths: []string{".Child.Child"}},
+		{I: m, Query: "$..name", Paths: []string{".name"}},
+		{I: l, Query: "$..[0]", Paths: []string{"[0]"}},
+	} {
+		q, err := CompileQuery(test.Query)
+		if err != nil {
+			t.Errorf("CompileQuery(%q): unexpected error: %s", test.Query, err)
+			continue
+		}
+
+		got := q.Paths(test.I)
+		if !reflect.DeepEqual(got, test.Paths) {
+			t.Errorf("CompileQuery(%q).Paths() = %q, expected %q", test.Query, got, test.Paths)
+		}
+	}
+}
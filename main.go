@@ -2,7 +2,9 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -17,6 +19,16 @@ const (
 	statusUnmarshalError = 4
 	statusDiffError      = 5
 	statusDiffMismatch   = 6
+	statusPatchError     = 7
+	statusMergeConflict  = 8
+)
+
+// stdinFile is the file name used to read an input from stdin.
+const stdinFile = "-"
+
+const (
+	outputJSONPatch  = "jsonpatch"
+	outputMergePatch = "mergepatch"
 )
 
 var (
@@ -25,31 +37,119 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case cmdPatch:
+			patchMain(readPatchConfig(os.Args[2:]))
+			return
+		case cmdMerge:
+			mergeMain(readMergeConfig(os.Args[2:]))
+			return
+		case cmdGitDriver:
+			gitDriverMain(readGitDriverConfig(os.Args[2:]))
+			return
+		}
+	}
+
+	conf := readConfig()
+	switch {
+	case conf.Recursive:
+		recursiveMain(conf)
+		return
+	case conf.Git:
+		gitMain(conf)
+		return
+	}
+
+	d, err := diffFiles(conf.diffOptions, conf.Files.LHS, conf.Files.RHS)
+	if err != nil {
+		fail(err)
+	}
+	printDiff(conf.diffOptions, d)
+	if d.Diff() != diff.Identical {
+		os.Exit(statusDiffMismatch)
+	}
+}
+
+// statusError is an error along with the status jaydiff exits with when it occurs.
+type statusError struct {
+	status int
+	err    error
+}
+
+func errorf(status int, format string, args ...interface{}) error {
+	return statusError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+func (e statusError) Error() string {
+	return e.err.Error()
+}
+
+// errorStatus returns the status jaydiff exits with when err occurs.
+func errorStatus(err error) int {
+	if e, ok := err.(statusError); ok {
+		return e.status
+	}
+
+	return statusDiffError
+}
+
+// fail prints err and exits with its status.
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+	os.Exit(errorStatus(err))
+}
+
+// diffFiles parses and compares lhsName and rhsName.
+func diffFiles(conf diffOptions, lhsName, rhsName string) (diff.Differ, error) {
 	var (
-		err                  error
 		lhs, rhs             interface{}
 		lhsCloser, rhsCloser io.Closer
+		err                  error
 	)
-	conf := readConfig()
+
+	lhsFormat := fileFormat(lhsName, conf.LHSFormat)
+	rhsFormat := fileFormat(rhsName, conf.RHSFormat)
 
 	switch conf.Stream {
 	case true:
-		lhs, lhsCloser = parseStream(conf.Files.LHS, conf.StreamLines)
+		lhs, lhsCloser, err = parseStream(lhsName, lhsFormat, conf.StreamLines, conf.UseNumber)
+		if err != nil {
+			return nil, err
+		}
 		defer lhsCloser.Close()
-		rhs, rhsCloser = parseStream(conf.Files.RHS, conf.StreamLines)
-		defer rhsCloser.Close()
-		if conf.StreamValidate {
-			lhs = singleValueForValidate(lhs.(diff.Stream), rhs.(HasMore))
+		rhs, rhsCloser, err = parseStream(rhsName, rhsFormat, conf.StreamLines, conf.UseNumber)
+		if err != nil {
+			return nil, err
 		}
+		defer rhsCloser.Close()
 	case false:
-		lhs = parseFile(conf.Files.LHS)
-		rhs = parseFile(conf.Files.RHS)
+		lhs, err = parseFile(lhsName, lhsFormat, conf.UseNumber)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err = parseFile(rhsName, rhsFormat, conf.UseNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return diffValues(conf, lhs, rhs)
+}
+
+// diffValues compares lhs and rhs, applying the ignore options.
+func diffValues(conf diffOptions, lhs, rhs interface{}) (diff.Differ, error) {
+	lhs, rhs = documentStreams(lhs, rhs)
+	if conf.StreamValidate {
+		lhs = singleValueForValidate(lhs.(diff.Stream), rhs.(HasMore))
 	}
 
 	d, err := diff.Diff(lhs, rhs, conf.Opts()...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: diff failed: %s\n", err)
-		os.Exit(statusDiffError)
+		return nil, errorf(statusDiffError, "diff failed: %s", err)
+	}
+	if conf.Reverse {
+		d = diff.Invert(d)
 	}
 
 	d, err = pruneIgnore(
@@ -58,13 +158,22 @@ func main() {
 		conf.IgnoreValues,
 		conf.StreamIgnoreExcess,
 		conf.Ignore,
+		conf.IgnoreQuery.Paths(d),
 	)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: ignoring failed: %s\n", err)
-		os.Exit(statusDiffError)
+		return nil, errorf(statusDiffError, "ignoring failed: %s", err)
 	}
 
-	if conf.OutputReport {
+	return d, nil
+}
+
+func printDiff(conf diffOptions, d diff.Differ) {
+	switch {
+	case conf.OutputFormat == outputJSONPatch:
+		printJSONPatch(d, conf.Indent)
+	case conf.OutputFormat == outputMergePatch:
+		printMergePatch(d, conf.Indent)
+	case conf.OutputReport:
 		ss, err := diff.Report(d, diff.Output(conf.output))
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to generate report: %s\n", err)
@@ -73,17 +182,14 @@ func main() {
 		for _, s := range ss {
 			fmt.Println(s)
 		}
-	} else {
+	default:
 		fmt.Println(d.StringIndent("", "", diff.Output(conf.output)))
 	}
-	if d.Diff() != diff.Identical {
-		os.Exit(statusDiffMismatch)
-	}
 }
 
-func pruneIgnore(d diff.Differ, ingoreExcess, ignoreValues, streamIgnoreExcess bool, ignore ignorePatterns) (diff.Differ, error) {
+func pruneIgnore(d diff.Differ, ingoreExcess, ignoreValues, streamIgnoreExcess bool, ignore ignorePatterns, ignoredPaths map[string]bool) (diff.Differ, error) {
 	return diff.Walk(d, func(parent diff.Differ, d diff.Differ, path string) (diff.Differ, error) {
-		if ignore.Match(path) {
+		if ignore.Match(path) || ignoredPaths[path] {
 			return diff.Ignore()
 		}
 
@@ -99,44 +205,107 @@ func pruneIgnore(d diff.Differ, ingoreExcess, ignoreValues, streamIgnoreExcess b
 	})
 }
 
-func parseFile(fname string) interface{} {
-	var err error
-	var val interface{}
+func printJSONPatch(d diff.Differ, indent string) {
+	ops, err := diff.JSONPatch(d)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to generate JSON Patch: %s\n", err)
+		os.Exit(statusDiffError)
+	}
+	b, err := json.MarshalIndent(ops, "", indent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to encode JSON Patch: %s\n", err)
+		os.Exit(statusDiffError)
+	}
+	fmt.Println(string(b))
+}
 
-	b, err := ioutil.ReadFile(fname)
+func printMergePatch(d diff.Differ, indent string) {
+	patch, err := diff.MergePatch(d)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: cannot read %s\n", fname)
-		os.Exit(statusReadError)
+		fmt.Fprintf(os.Stderr, "Error: Failed to generate JSON Merge Patch: %s\n", err)
+		os.Exit(statusDiffError)
 	}
-	err = json.Unmarshal(b, &val)
+	b, err := json.MarshalIndent(patch, "", indent)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: cannot parse %s: %s\n", fname, err)
-		os.Exit(statusUnmarshalError)
+		fmt.Fprintf(os.Stderr, "Error: Failed to encode JSON Merge Patch: %s\n", err)
+		os.Exit(statusDiffError)
+	}
+	fmt.Println(string(b))
+}
+
+func parseFile(fname, format string, useNumber bool) (interface{}, error) {
+	b, err := readFile(fname)
+	if err != nil {
+		return nil, errorf(statusReadError, "cannot read %s", fname)
 	}
 
-	return val
+	return parseBytes(fname, format, b, useNumber)
 }
 
-func parseStream(fname string, lineByLine bool) (diff.Stream, io.Closer) {
-	f, err := os.Open(fname)
+// mustParseFile is similar to parseFile, exiting on failure.
+func mustParseFile(fname, format string, useNumber bool) interface{} {
+	v, err := parseFile(fname, format, useNumber)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: cannot open %s\n", fname)
-		os.Exit(statusReadError)
+		fail(err)
 	}
 
-	if lineByLine {
-		return &LineByLineJSONStream{
-			Scanner: bufio.NewScanner(f),
-		}, f
+	return v
+}
+
+func parseBytes(fname, format string, b []byte, useNumber bool) (interface{}, error) {
+	val, err := decode(format, b, useNumber)
+	if err != nil {
+		return nil, errorf(statusUnmarshalError, "cannot parse %s: %s", fname, err)
 	}
 
-	return &diff.JSONStream{
-		Decoder: json.NewDecoder(f),
-	}, f
+	return val, nil
+}
+
+func parseStream(fname, format string, lineByLine, useNumber bool) (diff.Stream, io.Closer, error) {
+	f, err := openFile(fname)
+	if err != nil {
+		return nil, nil, errorf(statusReadError, "cannot open %s", fname)
+	}
+
+	s, err := readStream(fname, format, f, lineByLine, useNumber)
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+
+	return s, f, nil
+}
+
+func readStream(fname, format string, r io.Reader, lineByLine, useNumber bool) (diff.Stream, error) {
+	s, err := decodeStream(format, r, lineByLine, useNumber)
+	if err != nil {
+		return nil, errorf(statusReadError, "cannot read %s: %s", fname, err)
+	}
+
+	return s, nil
+}
+
+// readFile reads the content of fname, or of stdin if fname is "-".
+func readFile(fname string) ([]byte, error) {
+	if fname == stdinFile {
+		return ioutil.ReadAll(os.Stdin)
+	}
+
+	return ioutil.ReadFile(fname)
+}
+
+// openFile opens fname for reading, or returns stdin if fname is "-".
+func openFile(fname string) (io.ReadCloser, error) {
+	if fname == stdinFile {
+		return ioutil.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(fname)
 }
 
 type LineByLineJSONStream struct {
 	*bufio.Scanner
+	UseNumber bool
 
 	eof bool
 	i   int
@@ -148,7 +317,7 @@ func (s *LineByLineJSONStream) NextValue() (interface{}, error) {
 	}
 
 	if s.Scan() {
-		v, err := decodeJSON(s.Bytes())
+		v, err := decodeJSON(s.Bytes(), s.UseNumber)
 		if err != nil {
 			err = fmt.Errorf("decoding json line %d: %v", s.i, err)
 		}
@@ -166,12 +335,26 @@ func (s *LineByLineJSONStream) NextValue() (interface{}, error) {
 	return nil, err
 }
 
-func decodeJSON(b []byte) (interface{}, error) {
+// decodeJSON decodes b. Numbers are decoded as json.Number if useNumber is true.
+func decodeJSON(b []byte, useNumber bool) (interface{}, error) {
 	var v interface{}
 
-	err := json.Unmarshal(b, &v)
+	if !useNumber {
+		err := json.Unmarshal(b, &v)
+		return v, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err := dec.Decode(&v)
+	if err != nil {
+		return v, err
+	}
+	if _, err = dec.Token(); err != io.EOF {
+		return v, errors.New("invalid data after top-level value")
+	}
 
-	return v, err
+	return v, nil
 }
 
 type singleValueStream struct {
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yazgazan/jaydiff/diff"
+)
+
+const cmdMerge = "merge"
+
+// mergeMain merges the changes made to conf.Files.Base in conf.Files.Ours and conf.Files.Theirs,
+// printing the merged document and the conflicting paths. Numbers are decoded as json.Number, so
+// that the values are printed back unchanged.
+func mergeMain(conf mergeConfig) {
+	base := mustParseFile(conf.Files.Base, fileFormat(conf.Files.Base, ""), true)
+	ours := mustParseFile(conf.Files.Ours, fileFormat(conf.Files.Ours, ""), true)
+	theirs := mustParseFile(conf.Files.Theirs, fileFormat(conf.Files.Theirs, ""), true)
+
+	merged, conflicts, err := diff.Merge(base, ours, theirs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: merge failed: %s\n", err)
+		os.Exit(statusDiffError)
+	}
+
+	b, err := json.MarshalIndent(merged, "", conf.Indent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to encode merged value: %s\n", err)
+		os.Exit(statusDiffError)
+	}
+	fmt.Println(string(b))
+
+	for _, path := range conflicts {
+		fmt.Fprintf(os.Stderr, "Conflict: %s\n", path)
+	}
+	if len(conflicts) != 0 {
+		os.Exit(statusMergeConflict)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/yazgazan/jaydiff/diff"
+)
+
+const cmdPatch = "patch"
+
+// patchMain applies an RFC 6902 JSON Patch read from a file (such as the output of
+// --output=jsonpatch) to a document. As it only has the serialized operations, it uses
+// diff.ApplyJSONPatch: diff.Apply needs the diff tree the patch was generated from.
+// Numbers are decoded as json.Number, so that the values left untouched by the patch are
+// printed back unchanged.
+func patchMain(conf patchConfig) {
+	doc := mustParseFile(conf.Files.File, fileFormat(conf.Files.File, ""), true)
+	ops := parsePatch(conf.Files.Patch)
+
+	doc, err := diff.ApplyJSONPatch(doc, ops)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: patch failed: %s\n", err)
+		os.Exit(statusPatchError)
+	}
+
+	b, err := json.MarshalIndent(doc, "", conf.Indent)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to encode patched value: %s\n", err)
+		os.Exit(statusPatchError)
+	}
+	fmt.Println(string(b))
+}
+
+func parsePatch(fname string) []diff.PatchOperation {
+	var ops []diff.PatchOperation
+
+	b, err := readFile(fname)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot read %s\n", fname)
+		os.Exit(statusReadError)
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err = dec.Decode(&ops)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot parse %s: %s\n", fname, err)
+		os.Exit(statusUnmarshalError)
+	}
+
+	return ops
+}
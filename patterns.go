@@ -2,6 +2,7 @@ package main
 
 import (
 	"github.com/gobwas/glob"
+	"github.com/yazgazan/jaydiff/diff"
 	"github.com/yazgazan/jaydiff/jpath"
 )
 
@@ -35,3 +36,27 @@ func (p ignorePatterns) Match(s string) bool {
 
 	return false
 }
+
+type ignoreQueries []*jpath.Query
+
+func (q *ignoreQueries) UnmarshalFlag(s string) error {
+	query, err := jpath.CompileQuery(s)
+	if err != nil {
+		return err
+	}
+	*q = append(*q, query)
+
+	return nil
+}
+
+// Paths returns the paths of the nodes of d matched by the queries (see diff.MatchQuery).
+func (q ignoreQueries) Paths(d diff.Differ) map[string]bool {
+	paths := map[string]bool{}
+	for _, query := range q {
+		for _, path := range diff.MatchQuery(d, query) {
+			paths[path.String()] = true
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/yazgazan/jaydiff/diff"
+)
+
+// recursiveMain compares the files of the conf.Files.LHS and conf.Files.RHS
+// directories, pairing them by relative path. Only the files that differ are
+// printed, preceded by a header naming them, followed by a summary. Files that
+// cannot be compared (i.e cannot be parsed) are reported on stderr and counted as
+// failed, jaydiff then exiting with the status of the first failure.
+// Files with an unknown extension (unless the format is forced) and symbolic links
+// are skipped, with a notice.
+func recursiveMain(conf config) {
+	var compared, differ, lhsOnly, rhsOnly, failed int
+	// status is the exit status of the first failure
+	status := 0
+
+	lhsFiles := listFiles(conf.Files.LHS, conf.LHSFormat)
+	rhsFiles := listFiles(conf.Files.RHS, conf.RHSFormat)
+
+	for len(lhsFiles) != 0 || len(rhsFiles) != 0 {
+		switch {
+		case len(rhsFiles) == 0 || (len(lhsFiles) != 0 && lhsFiles[0] < rhsFiles[0]):
+			fmt.Printf("Only in %s: %s\n", conf.Files.LHS, lhsFiles[0])
+			lhsFiles = lhsFiles[1:]
+			lhsOnly++
+		case len(lhsFiles) == 0 || rhsFiles[0] < lhsFiles[0]:
+			fmt.Printf("Only in %s: %s\n", conf.Files.RHS, rhsFiles[0])
+			rhsFiles = rhsFiles[1:]
+			rhsOnly++
+		default:
+			lhsName := filepath.Join(conf.Files.LHS, lhsFiles[0])
+			rhsName := filepath.Join(conf.Files.RHS, rhsFiles[0])
+			lhsFiles, rhsFiles = lhsFiles[1:], rhsFiles[1:]
+
+			d, err := diffFiles(conf.diffOptions, lhsName, rhsName)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+				if failed == 0 {
+					status = errorStatus(err)
+				}
+				failed++
+				continue
+			}
+			compared++
+			if d.Diff() == diff.Identical {
+				continue
+			}
+			fmt.Printf("jaydiff %s %s\n", lhsName, rhsName)
+			printDiff(conf.diffOptions, d)
+			differ++
+		}
+	}
+
+	fmt.Printf(
+		"%d %s compared, %d differ, %d only in %s, %d only in %s, %d failed\n",
+		compared, plural(compared, "file", "files"), differ, lhsOnly, conf.Files.LHS, rhsOnly, conf.Files.RHS, failed,
+	)
+	if failed != 0 {
+		os.Exit(status)
+	}
+	if differ != 0 || lhsOnly != 0 || rhsOnly != 0 {
+		os.Exit(statusDiffMismatch)
+	}
+}
+
+// listFiles returns the sorted paths of the regular files found in dir, relative to dir.
+// Unless format is set, files with an unknown extension are skipped. Skipped files (including
+// symbolic links, which are not followed) are reported.
+func listFiles(dir, format string) []string {
+	var files []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.IsDir():
+			return nil
+		case info.Mode()&os.ModeSymlink != 0:
+			fmt.Printf("Skipping %s: symbolic link\n", path)
+			return nil
+		case !info.Mode().IsRegular():
+			fmt.Printf("Skipping %s: not a regular file\n", path)
+			return nil
+		}
+		if _, ok := extensionFormat(path); !ok && format == "" {
+			fmt.Printf("Skipping %s: unknown format\n", path)
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: cannot read directory %s: %s\n", dir, err)
+		os.Exit(statusReadError)
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+// checkDirectories exits with a usage error if one of dirs is not a directory. Directories that
+// cannot be read are reported when listing their files.
+func checkDirectories(cmd string, dirs ...string) {
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err == nil && !info.IsDir() {
+			fmt.Fprintf(os.Stderr, "%s is not a directory, --recursive expects directories. See %s --help\n", dir, cmd)
+			os.Exit(statusUsage)
+		}
+	}
+}
+
+// plural returns singular if n is 1, plural otherwise.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+
+	return plural
+}
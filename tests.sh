@@ -56,6 +56,500 @@ if [[ $FAILED -ne 0 ]]; then
 	exit 1
 fi
 
+echo "./jaydiff --report (stdin):"
+./jaydiff --report \
+	- test_files/rhs.json < test_files/lhs.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --slice-myers (moves):"
+./jaydiff --report --slice-myers \
+	test_files/lhs_steps.json test_files/rhs_steps.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --slice-myers (similar elements):"
+./jaydiff --report --slice-myers \
+	test_files/lhs_jobs.json test_files/rhs_jobs.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --float-tolerance:"
+./jaydiff --report --float-tolerance=0.0001 \
+	test_files/lhs_metrics.json test_files/rhs_metrics.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --use-number:"
+./jaydiff --report --use-number \
+	test_files/lhs_ids.json test_files/rhs_ids.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --use-number (JSON and YAML):"
+OUT=$(./jaydiff --report --use-number \
+	test_files/lhs_ids.json test_files/rhs_ids.yaml)
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+elif [[ "$OUT" != *".id: 1152921504606846976"* || "$OUT" == *".parent"* || "$OUT" == *".score"* ]]; then
+	echo "FAIL: unexpected output: $OUT"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --inline-strings:"
+./jaydiff --report --inline-strings \
+	test_files/lhs_query.json test_files/rhs_query.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --multiline-strings:"
+./jaydiff --report --multiline-strings \
+	test_files/lhs_script.json test_files/rhs_script.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --json --multiline-strings:"
+./jaydiff --json --multiline-strings \
+	test_files/lhs_script.json test_files/rhs_script.json
+CODE=$?
+if [[ $CODE -ne 2 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --ignore-query:"
+./jaydiff --report --ignore-query '$.users[?(@.id == 2)]' \
+	test_files/lhs_users.json test_files/rhs_users.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --ignore-query (YAML stream):"
+./jaydiff --report --ignore-query '$..replicas' \
+	test_files/lhs_stream.yaml test_files/rhs_stream.yaml
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --array-key:"
+./jaydiff --report --array-key '.users[]=id' \
+	test_files/lhs_users.json test_files/rhs_users.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --unordered:"
+./jaydiff --report --unordered \
+	test_files/lhs_unordered.json test_files/rhs_unordered.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --recursive:"
+./jaydiff --report --recursive \
+	test_files/lhs_dir test_files/rhs_dir
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --recursive (invalid file):"
+ln -s a.json test_files/lhs_invalid_dir/link.json
+OUT=$(./jaydiff --report --recursive \
+	test_files/lhs_invalid_dir test_files/rhs_invalid_dir)
+CODE=$?
+rm -f test_files/lhs_invalid_dir/link.json
+if [[ $CODE -ne 4 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+elif [[ "$OUT" != *"link.json: symbolic link"*"notes.txt: unknown format"*"1 file compared, 1 differ"*"1 failed"* ]]; then
+	echo "FAIL: unexpected output: $OUT"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --recursive (not a directory):"
+./jaydiff --recursive test_files/lhs.json test_files/rhs.json
+CODE=$?
+if [[ $CODE -ne 2 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --git:"
+./jaydiff --git \
+	HEAD HEAD test_files/lhs.json
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --git (revision containing colons):"
+./jaydiff --git \
+	':/[[:alpha:]]' HEAD test_files/lhs.json
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff git-driver:"
+./jaydiff git-driver --report \
+	lhs.json test_files/lhs.json 0000000 100644 test_files/rhs.json 0000000 100644
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report (yaml):"
+./jaydiff --report \
+	test_files/lhs.yaml test_files/rhs.yaml
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report (msgpack):"
+./jaydiff --report \
+	test_files/lhs.msgpack test_files/rhs.msgpack
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report (cbor):"
+./jaydiff --report \
+	test_files/lhs.cbor test_files/rhs.cbor
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report (toml):"
+./jaydiff --report \
+	test_files/lhs.toml test_files/rhs.toml
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --ignore .project.dependencies.dependency[].@scope (xml):"
+./jaydiff --report --ignore '.project.dependencies.dependency\[\].@scope' \
+	test_files/lhs.xml test_files/rhs.xml
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff (xml namespaces):"
+OUT=$(./jaydiff test_files/lhs.xml test_files/rhs.xml)
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+elif [[ "$OUT" == *"xmlns"* ]]; then
+	echo "FAIL: unexpected output: $OUT"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff (xml content after the root element):"
+printf '<project></project><junk' > test_files/junk.tmp.xml
+./jaydiff test_files/lhs.xml test_files/junk.tmp.xml
+CODE=$?
+rm -f test_files/junk.tmp.xml
+if [[ $CODE -ne 4 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --key-columns=id --ignore [].price (csv):"
+./jaydiff --report --key-columns=id --ignore '\[\].price' \
+	test_files/lhs.csv test_files/rhs.csv
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report --ignore [].spec.replicas (yaml stream):"
+./jaydiff --report --ignore '\[\].spec.replicas' \
+	test_files/lhs_stream.yaml test_files/rhs_stream.yaml
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --report (yaml stream and single document):"
+OUT=$(./jaydiff --report \
+	test_files/lhs_service.yaml test_files/rhs_stream.yaml)
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+elif [[ "$OUT" != "+ [1]: "* ]]; then
+	echo "FAIL: unexpected output: $OUT"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --reverse --report:"
+./jaydiff --reverse --report \
+	test_files/lhs.json test_files/rhs.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --output=jsonpatch:"
+./jaydiff --output=jsonpatch \
+	test_files/lhs.json test_files/rhs.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff patch:"
+./jaydiff --output=jsonpatch test_files/lhs.json test_files/rhs.json > test_files/patch.tmp.json
+./jaydiff patch test_files/lhs.json test_files/patch.tmp.json > test_files/patched.tmp.json
+./jaydiff test_files/patched.tmp.json test_files/rhs.json
+CODE=$?
+rm -f test_files/patch.tmp.json test_files/patched.tmp.json
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff patch (large numbers):"
+echo '[{"op": "replace", "path": "/score", "value": 2}]' > test_files/patch.tmp.json
+OUT=$(./jaydiff patch test_files/lhs_ids.json test_files/patch.tmp.json)
+CODE=$?
+rm -f test_files/patch.tmp.json
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+elif [[ "$OUT" != *'"id": 1152921504606846977'* || "$OUT" != *'"score": 2'* ]]; then
+	echo "FAIL: unexpected output: $OUT"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff patch (moves):"
+./jaydiff --output=jsonpatch --slice-myers test_files/lhs_steps.json test_files/rhs_steps.json > test_files/patch.tmp.json
+./jaydiff patch test_files/lhs_steps.json test_files/patch.tmp.json > test_files/patched.tmp.json
+./jaydiff test_files/patched.tmp.json test_files/rhs_steps.json
+CODE=$?
+rm -f test_files/patch.tmp.json test_files/patched.tmp.json
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff patch (ignored excess):"
+./jaydiff --output=jsonpatch --slice-myers --ignore-excess test_files/lhs.json test_files/rhs.json > test_files/patch.tmp.json
+./jaydiff patch test_files/lhs.json test_files/patch.tmp.json > test_files/patched.tmp.json
+./jaydiff --slice-myers --ignore-excess test_files/patched.tmp.json test_files/rhs.json
+CODE=$?
+rm -f test_files/patch.tmp.json test_files/patched.tmp.json
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff merge:"
+./jaydiff merge \
+	test_files/base_merge.json test_files/ours_merge.json test_files/theirs_merge.json
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff merge (large numbers):"
+OUT=$(./jaydiff merge \
+	test_files/lhs_ids.json test_files/lhs_ids.json test_files/rhs_ids.json)
+CODE=$?
+if [[ $CODE -ne 0 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+elif [[ "$OUT" != *'"id": 1152921504606846976'* || "$OUT" != *'"parent": 1152921504606846900'* ]]; then
+	echo "FAIL: unexpected output: $OUT"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff merge (conflicts):"
+./jaydiff merge \
+	test_files/lhs.json test_files/rhs.json test_files/lhs_validate.json
+CODE=$?
+if [[ $CODE -ne 8 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
+echo "./jaydiff --output=mergepatch:"
+./jaydiff --output=mergepatch \
+	test_files/lhs.json test_files/rhs.json
+CODE=$?
+if [[ $CODE -ne 6 ]]; then
+	echo "FAIL with code $CODE"
+	FAILED=1
+else
+	echo "OK"
+fi
+echo
+
 echo "./jaydiff --report --ignore-excess --show-types:"
 ./jaydiff --report --ignore-excess --indent='    ' --show-types \
 	test_files/lhs.json test_files/rhs.json